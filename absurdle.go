@@ -0,0 +1,21 @@
+package main
+
+// Evaluate returns the feedback for a submitted guess. In ABSURDLE mode
+// there is no hidden word: the engine keeps the largest set of candidates
+// consistent with everything shown so far, and Word only tracks one of them
+// so the score screen has something to reveal.
+func (g *Game) Evaluate(guess []rune) Pattern {
+	sw := newSolverWord(guess)
+
+	if g.Mode != ABSURDLE {
+		return Score(sw, newSolverWord(g.Word))
+	}
+
+	p, bucket := LargestBucket(sw, g.Candidates)
+	g.Candidates = bucket
+
+	w := bucket[0].runes
+	g.Word = w[:]
+
+	return p
+}
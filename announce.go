@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"strings"
+)
+
+// Announcer hands text to whatever reads the game out loud: a screen
+// reader, a speech synthesiser or just a terminal.
+type Announcer interface {
+	Announce(msg string)
+}
+
+// WriterAnnouncer prints every message on its own line, for terminals and
+// screen readers that follow them.
+type WriterAnnouncer struct {
+	W io.Writer
+}
+
+func (a WriterAnnouncer) Announce(msg string) {
+	fmt.Fprintln(a.W, msg)
+}
+
+// SpeechAnnouncer speaks through speech-dispatcher's spd-say. Messages are
+// queued by spd-say itself, so announcing never blocks the game; each
+// spd-say is waited for in the background so it does not linger as a
+// zombie.
+type SpeechAnnouncer struct {
+	Command string
+}
+
+func (a SpeechAnnouncer) Announce(msg string) {
+	cmd := a.Command
+	if cmd == "" {
+		cmd = "spd-say"
+	}
+
+	c := exec.Command(cmd, "--language", "ru", msg)
+	if err := c.Start(); err != nil {
+		log.Println(err.Error())
+		return
+	}
+
+	go c.Wait()
+}
+
+func (g *Game) Announce(msg string) {
+	if g.Announcer != nil {
+		g.Announcer.Announce(msg)
+	}
+}
+
+var statusNames = map[LetterStatus]string{
+	GUESSED: "на месте",
+	PRESENT: "есть в слове",
+	WRONG:   "нет в слове",
+}
+
+// DescribeRow spells out the feedback of submitted row r, as in
+// "к — на месте, о — есть в слове, ...".
+func (g *Game) DescribeRow(r int) string {
+	w := g.GuessedWords[r]
+	parts := make([]string, len(w))
+
+	for i, l := range w {
+		parts[i] = fmt.Sprintf("%c — %s", l, statusNames[g.GetLetterStatus(r, i, l)])
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// AnnounceSubmit reads out the row just submitted and, when the round is
+// over, how it ended.
+func (g *Game) AnnounceSubmit() {
+	r := g.LastSubmitted
+	g.Announce(fmt.Sprintf("%s: %s", string(g.GuessedWords[r]), g.DescribeRow(r)))
+
+	switch {
+	case g.IsWordGuessed():
+		g.Announce(fmt.Sprintf("Угадано с попытки %d из 6", len(g.GuessedWords)))
+	case g.Stage == SCORE:
+		g.Announce("Попытки кончились, слово: " + string(g.Word))
+	}
+}
+
+// DescribeBoard is a text-only account of the whole board: every
+// submitted row with its feedback, the row being typed, revealed letters
+// and the attempts and hints left.
+func (g *Game) DescribeBoard() string {
+	lines := make([]string, 0, 10)
+
+	for r := 0; r <= g.LastSubmitted; r++ {
+		lines = append(lines, fmt.Sprintf("%d. %s: %s", r+1, string(g.GuessedWords[r]), g.DescribeRow(r)))
+	}
+
+	if g.Stage == SCORE {
+		if g.IsWordGuessed() {
+			lines = append(lines, fmt.Sprintf("Угадано с попытки %d из 6", len(g.GuessedWords)))
+		} else {
+			lines = append(lines, "Попытки кончились, слово: "+string(g.Word))
+		}
+	}
+
+	if g.Stage == GAME {
+		r := g.CurrentRow()
+		typed := "пусто"
+		if r < len(g.GuessedWords) && len(g.GuessedWords[r]) > 0 {
+			typed = string(g.GuessedWords[r])
+		}
+		lines = append(lines, fmt.Sprintf("%d. набрано: %s", r+1, typed))
+
+		for i := range g.Word {
+			if l, ok := g.RevealedLetter(i); ok {
+				lines = append(lines, fmt.Sprintf("Подсказка: %d буква — %c", i+1, l))
+			}
+		}
+
+		lines = append(lines, fmt.Sprintf("Осталось попыток: %d, подсказок: %d", 6-r, g.HintsLeft()))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestAnnounceFeedback(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	var out bytes.Buffer
+	h.g.Announcer = WriterAnnouncer{W: &out}
+
+	h.guess("копна")
+	want := "копна: к — нет в слове, о — есть в слове, п — нет в слове, н — есть в слове, а — есть в слове\n"
+	if out.String() != want {
+		t.Fatalf("announced %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	h.guess("ккккк")
+	if out.String() != "Такого слова нет в словаре\n" {
+		t.Fatalf("invalid word announced %q", out.String())
+	}
+
+	h.key(ebiten.KeyEscape)
+	out.Reset()
+	h.guess("вазон")
+	if !strings.HasSuffix(out.String(), "Угадано с попытки 2 из 6\n") {
+		t.Fatalf("win announced %q", out.String())
+	}
+}
+
+func TestDescribeBoard(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	var out bytes.Buffer
+	h.g.Announcer = WriterAnnouncer{W: &out}
+
+	h.guess("сазан")
+	h.key(ebiten.KeyF1)
+	h.typeRunes("ко")
+	out.Reset()
+	h.key(ebiten.KeyF5)
+
+	want := strings.Join([]string{
+		"1. сазан: с — нет в слове, а — на месте, з — на месте, а — есть в слове, н — на месте",
+		"2. набрано: ко",
+		"Подсказка: 1 буква — в",
+		"Осталось попыток: 5, подсказок: 2",
+	}, "\n") + "\n"
+
+	if out.String() != want {
+		t.Fatalf("board described as\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestDescribeFreshBoard(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	h.key(ebiten.KeyF5)
+
+	var out bytes.Buffer
+	h.g.Announcer = WriterAnnouncer{W: &out}
+	h.key(ebiten.KeyF5)
+
+	want := "1. набрано: пусто\nОсталось попыток: 6, подсказок: 3\n"
+	if out.String() != want {
+		t.Fatalf("fresh board described as %q, want %q", out.String(), want)
+	}
+}
+
+func TestDescribeScoreScreen(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	var out bytes.Buffer
+	h.g.Announcer = WriterAnnouncer{W: &out}
+
+	h.guess("вазон")
+	out.Reset()
+	h.key(ebiten.KeyF5)
+
+	want := strings.Join([]string{
+		"1. вазон: в — на месте, а — на месте, з — на месте, о — на месте, н — на месте",
+		"Угадано с попытки 1 из 6",
+	}, "\n") + "\n"
+
+	if out.String() != want {
+		t.Fatalf("score screen described as\n%s\nwant\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	atlasColumns = 16
+	atlasRows    = 16
+)
+
+// glyphAtlas keeps the glyphs of one font at one scale rasterised on a
+// single texture, so a letter is drawn with one DrawImage instead of a rect
+// per lit pixel. Glyphs are added to the grid the first time they are
+// drawn, each in a cell of the font's cell size.
+type glyphAtlas struct {
+	font   *Font
+	scale  int
+	image  *ebiten.Image
+	glyphs map[rune]*ebiten.Image
+}
+
+type atlasKey struct {
+	font  *Font
+	scale int
+}
+
+var (
+	atlases = map[atlasKey]*glyphAtlas{}
+	glyphOp ebiten.DrawImageOptions
+)
+
+func atlasFor(f *Font, scale int) *glyphAtlas {
+	k := atlasKey{f, scale}
+	a, ok := atlases[k]
+	if !ok {
+		a = &glyphAtlas{
+			font:   f,
+			scale:  scale,
+			image:  ebiten.NewImage(f.CellWidth*scale*atlasColumns, f.CellHeight*scale*atlasRows),
+			glyphs: map[rune]*ebiten.Image{},
+		}
+		atlases[k] = a
+	}
+	return a
+}
+
+// glyph returns the atlas cell holding l, rasterising it in white on first
+// use. Once the atlas is full, new letters share a cell that is redrawn for
+// each of them.
+func (a *glyphAtlas) glyph(l rune) *ebiten.Image {
+	if g, ok := a.glyphs[l]; ok {
+		return g
+	}
+
+	w, h := a.font.CellWidth*a.scale, a.font.CellHeight*a.scale
+	i := min(len(a.glyphs), atlasColumns*atlasRows-1)
+	x, y := (i%atlasColumns)*w, (i/atlasColumns)*h
+
+	cell := a.image.SubImage(image.Rect(x, y, x+w, y+h)).(*ebiten.Image)
+	cell.Clear()
+
+	if g := a.font.Glyph(l); g.Width > 0 {
+		drawBits(a.image, g.Bits, g.Width, float32(x+g.X*a.scale), float32(y+g.Y*a.scale), float32(a.scale), color.White)
+	}
+
+	if i < atlasColumns*atlasRows-1 {
+		a.glyphs[l] = cell
+	}
+
+	return cell
+}
+
+// drawGlyph draws l from the atlas of f at scale s, tinted with c and
+// snapped to the pixel grid.
+func drawGlyph(screen *ebiten.Image, f *Font, l rune, x, y, s float32, c color.Color) {
+	scale := max(1, int(s))
+
+	glyphOp.GeoM.Reset()
+	glyphOp.GeoM.Translate(math.Round(float64(x)), math.Round(float64(y)))
+	glyphOp.ColorScale.Reset()
+	glyphOp.ColorScale.ScaleWithColor(c)
+
+	drawCalls++
+	screen.DrawImage(atlasFor(f, scale).glyph(l), &glyphOp)
+}
@@ -3,8 +3,8 @@ package main
 import (
 	"fmt"
 	"image/color"
+	"math"
 	"strings"
-	"unicode/utf8"
 
 	"github.com/e-kucheriavyi/five-letters/pallete"
 	"github.com/hajimehoshi/ebiten/v2"
@@ -17,87 +17,186 @@ const (
 	ShakeValue = 600
 )
 
+// drawCalls counts calls into ebiten's drawing API, so benchmarks can tell
+// how many a frame takes.
+var drawCalls int
+
+func fillRect(screen *ebiten.Image, x, y, w, h float32, c color.Color) {
+	drawCalls++
+	vector.FillRect(screen, x, y, w, h, c, false)
+}
+
+func strokeRect(screen *ebiten.Image, x, y, w, h, stroke float32, c color.Color) {
+	drawCalls++
+	vector.StrokeRect(screen, x, y, w, h, stroke, c, false)
+}
+
 func (g *Game) StartShaking() {
+	g.PlaySound(SOUND_ERROR)
+	g.Vibrate(HAPTIC_ERROR)
+
+	if g.AnimationFactor() == 0 {
+		return
+	}
 	g.ShakeTimer = ShakeValue
 }
 
-var backspaceMap = &[]byte{
-	0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 1, 1, 1, 1, 1, 1,
-	0, 0, 1, 0, 0, 0, 0, 0, 1,
-	0, 1, 0, 0, 1, 0, 1, 0, 1,
-	1, 0, 0, 0, 0, 1, 0, 0, 1,
-	0, 1, 0, 0, 1, 0, 1, 0, 1,
-	0, 0, 1, 0, 0, 0, 0, 0, 1,
-	0, 0, 0, 1, 1, 1, 1, 1, 1,
-	0, 0, 0, 0, 0, 0, 0, 0, 0,
+// iconFont holds the key icons as glyphs, so they are drawn from the glyph
+// atlas like letters.
+var iconFont = &Font{
+	Name:       "icons",
+	CellWidth:  9,
+	CellHeight: 9,
+	glyphs: map[rune]*Glyph{
+		'-': {Advance: 9, Width: 9, Height: 9, Bits: []byte{
+			0, 0, 0, 0, 0, 0, 0, 0, 0,
+			0, 0, 0, 1, 1, 1, 1, 1, 1,
+			0, 0, 1, 0, 0, 0, 0, 0, 1,
+			0, 1, 0, 0, 1, 0, 1, 0, 1,
+			1, 0, 0, 0, 0, 1, 0, 0, 1,
+			0, 1, 0, 0, 1, 0, 1, 0, 1,
+			0, 0, 1, 0, 0, 0, 0, 0, 1,
+			0, 0, 0, 1, 1, 1, 1, 1, 1,
+			0, 0, 0, 0, 0, 0, 0, 0, 0,
+		}},
+	},
+}
+
+// Metrics are the sizes the layout is built from, derived from the window
+// size so tiles and keys scale with it. Everything is in device pixels;
+// Scale is the device scale factor the fixed sizes were multiplied by.
+type Metrics struct {
+	W         float32
+	H         float32
+	Scale     float32
+	Landscape bool
+	Padding   float32
+	Gap       float32
+	Header    float32
+	KeySide   float32
+	TileSide  float32
+	HintSide  float32
+}
+
+const (
+	layoutPadding = 8
+	headerHeight  = 64
+	tileGap       = 8
+)
+
+// NewMetrics picks the arrangement for a w×h window. Windows clearly wider
+// than tall put the keyboard next to the board, everything else stacks it
+// below. Key and tile sides are whole pixels so glyphs land on the pixel
+// grid.
+func NewMetrics(w, h, scale float32) Metrics {
+	m := Metrics{W: w, H: h, Scale: scale, Landscape: w > h*1.25}
+
+	m.Padding = m.Px(layoutPadding)
+	m.Gap = m.Px(keyGap)
+	m.Header = m.Px(headerHeight)
+	rowGap := m.Px(keyRowGap)
+	tiles := m.Px(tileGap)
+
+	innerW := w - m.Padding*2
+	innerH := h - m.Padding*2
+
+	boardW := innerW
+	keyboardW := innerW
+	boardH := innerH - m.Header - m.Padding
+
+	if m.Landscape {
+		boardW = innerW*0.45 - m.Padding/2
+		keyboardW = innerW - boardW - m.Padding
+	}
+
+	m.KeySide = floor(min((keyboardW-m.Gap*11)/12, (innerH*0.3-rowGap*2)/3))
+
+	if !m.Landscape {
+		boardH -= m.KeySide*3 + rowGap*2 + m.Padding
+	}
+
+	m.TileSide = floor(min((boardH-tiles*5)/6, (boardW-tiles*4)/5, m.KeySide*1.1))
+	m.HintSide = floor(min(m.KeySide, m.Header-m.Px(8)))
+
+	return m
+}
+
+// Px converts a size in logical pixels into whole device pixels.
+func (m Metrics) Px(v float32) float32 {
+	return max(1, float32(math.Round(float64(v*m.Scale))))
+}
+
+func floor(v float32) float32 {
+	return float32(math.Floor(float64(v)))
 }
 
-func attemptItem(r, i int) *la.NodeItem {
+func attemptItem(m Metrics, r, i int) *la.NodeItem {
 	return la.Node(
 		la.Id(fmt.Sprintf("attempt_%d_%d", r, i)),
-		la.Width(la.Fix(attemptItemSide)),
-		la.Height(la.Fix(attemptItemSide)),
+		la.Width(la.Fix(m.TileSide)),
+		la.Height(la.Fix(m.TileSide)),
 	)
 }
 
-func attemptRow(r int) *la.NodeItem {
+func attemptRow(m Metrics, r int) *la.NodeItem {
 	return la.Node(
 		la.Id(fmt.Sprintf("attempt-row_%d", r)),
 		la.Row(),
-		la.Gap(8),
+		la.Gap(m.Px(tileGap)),
 		la.Children(
-			attemptItem(r, 0),
-			attemptItem(r, 1),
-			attemptItem(r, 2),
-			attemptItem(r, 3),
-			attemptItem(r, 4),
+			attemptItem(m, r, 0),
+			attemptItem(m, r, 1),
+			attemptItem(m, r, 2),
+			attemptItem(m, r, 3),
+			attemptItem(m, r, 4),
 		),
 	)
 }
 
-func keyNode(key rune) *la.NodeItem {
+func keyNode(side float32, key rune) *la.NodeItem {
 	return la.Node(
 		la.Id(fmt.Sprintf("key_%c", key)),
-		la.Width(la.Fix(keySide)),
-		la.Height(la.Fix(keySide)),
+		la.Width(la.Fix(side)),
+		la.Height(la.Fix(side)),
 	)
 }
 
-func growKeyNode(key rune) *la.NodeItem {
+func growKeyNode(side float32, key rune) *la.NodeItem {
 	return la.Node(
 		la.Id(fmt.Sprintf("key_%c", key)),
 		la.Width(la.Grow(1)),
-		la.Height(la.Fix(keySide)),
+		la.Height(la.Fix(side)),
 	)
 }
 
-func keyboardNode() *la.NodeItem {
+func keyboardNode(m Metrics) *la.NodeItem {
+	k := m.KeySide
+
 	return la.Node(
 		la.Id("keyboard"),
 		la.Column(),
 		la.Width(la.Grow(1)),
-		la.Gap(keyRowGap),
+		la.Gap(m.Px(keyRowGap)),
 		la.Children(
 			la.Node(
 				la.Id("keyboard_row_0"),
 				la.Row(),
 				la.Width(la.Grow(1)),
-				la.Gap(keyGap),
+				la.Gap(m.Gap),
 				la.Children(
 					spacer(1),
-					keyNode('й'),
-					keyNode('ц'),
-					keyNode('у'),
-					keyNode('к'),
-					keyNode('е'),
-					keyNode('н'),
-					keyNode('г'),
-					keyNode('ш'),
-					keyNode('щ'),
-					keyNode('з'),
-					keyNode('х'),
-					keyNode('ъ'),
+					keyNode(k, 'й'),
+					keyNode(k, 'ц'),
+					keyNode(k, 'у'),
+					keyNode(k, 'к'),
+					keyNode(k, 'е'),
+					keyNode(k, 'н'),
+					keyNode(k, 'г'),
+					keyNode(k, 'ш'),
+					keyNode(k, 'щ'),
+					keyNode(k, 'з'),
+					keyNode(k, 'х'),
+					keyNode(k, 'ъ'),
 					spacer(1),
 				),
 			),
@@ -105,20 +204,20 @@ func keyboardNode() *la.NodeItem {
 				la.Id("keyboard_row_1"),
 				la.Row(),
 				la.Width(la.Grow(1)),
-				la.Gap(8),
+				la.Gap(m.Gap),
 				la.Children(
 					spacer(1),
-					keyNode('ф'),
-					keyNode('ы'),
-					keyNode('в'),
-					keyNode('а'),
-					keyNode('п'),
-					keyNode('р'),
-					keyNode('о'),
-					keyNode('л'),
-					keyNode('д'),
-					keyNode('ж'),
-					keyNode('э'),
+					keyNode(k, 'ф'),
+					keyNode(k, 'ы'),
+					keyNode(k, 'в'),
+					keyNode(k, 'а'),
+					keyNode(k, 'п'),
+					keyNode(k, 'р'),
+					keyNode(k, 'о'),
+					keyNode(k, 'л'),
+					keyNode(k, 'д'),
+					keyNode(k, 'ж'),
+					keyNode(k, 'э'),
 					spacer(1),
 				),
 			),
@@ -126,19 +225,19 @@ func keyboardNode() *la.NodeItem {
 				la.Id("keyboard_row_2"),
 				la.Row(),
 				la.Width(la.Grow(1)),
-				la.Gap(8),
+				la.Gap(m.Gap),
 				la.Children(
-					growKeyNode('+'),
-					keyNode('я'),
-					keyNode('ч'),
-					keyNode('с'),
-					keyNode('м'),
-					keyNode('и'),
-					keyNode('т'),
-					keyNode('ь'),
-					keyNode('б'),
-					keyNode('ю'),
-					growKeyNode('-'),
+					growKeyNode(k, '+'),
+					keyNode(k, 'я'),
+					keyNode(k, 'ч'),
+					keyNode(k, 'с'),
+					keyNode(k, 'м'),
+					keyNode(k, 'и'),
+					keyNode(k, 'т'),
+					keyNode(k, 'ь'),
+					keyNode(k, 'б'),
+					keyNode(k, 'ю'),
+					growKeyNode(k, '-'),
 				),
 			),
 		),
@@ -152,61 +251,114 @@ func spacer(v float32) *la.NodeItem {
 	)
 }
 
-func CreateLayout() *la.OutputItem {
-	root := la.Node(
-		la.Id("root"),
-		la.Gap(8),
-		la.Padding(8),
-		la.Width(la.Fix(screenW)),
-		la.Height(la.Fix(screenH)),
-		la.Column(),
+func headerNode(m Metrics) *la.NodeItem {
+	return la.Node(
+		la.Id("header"),
+		la.Row(),
+		la.Gap(m.Gap),
+		la.Height(la.Fix(m.Header)),
+		la.Width(la.Grow(1)),
+		la.Children(
+			keyNode(m.HintSide, '?'),
+			spacer(1),
+			keyNode(m.HintSide, SETTINGS_KEY),
+			keyNode(m.HintSide, '!'),
+		),
+	)
+}
+
+func attemptsNode(m Metrics) *la.NodeItem {
+	return la.Node(
+		la.Id("top"),
+		la.Row(),
+		la.Width(la.Grow(1)),
+		la.Height(la.Fit()),
 		la.Children(
 			la.Node(
-				la.Id("header"),
-				la.Height(la.Fix(64)),
+				la.Id("top-spacer-left"),
 				la.Width(la.Grow(1)),
 			),
 			la.Node(
-				la.Id("top"),
-				la.Row(),
-				la.Width(la.Grow(1)),
+				la.Id("attempts"),
+				la.Column(),
+				la.Width(la.Fit()),
 				la.Height(la.Fit()),
+				la.Gap(m.Px(tileGap)),
 				la.Children(
-					la.Node(
-						la.Id("top-spacer-left"),
-						la.Width(la.Grow(1)),
-					),
-					la.Node(
-						la.Id("attempts"),
-						la.Column(),
-						la.Width(la.Fit()),
-						la.Height(la.Fit()),
-						la.Gap(8),
-						la.Children(
-							attemptRow(0),
-							attemptRow(1),
-							attemptRow(2),
-							attemptRow(3),
-							attemptRow(4),
-							attemptRow(5),
-						),
-					),
-					la.Node(
-						la.Id("top-spacer-right"),
-						la.Width(la.Grow(1)),
-					),
+					attemptRow(m, 0),
+					attemptRow(m, 1),
+					attemptRow(m, 2),
+					attemptRow(m, 3),
+					attemptRow(m, 4),
+					attemptRow(m, 5),
 				),
 			),
 			la.Node(
-				la.Id("bottom"),
+				la.Id("top-spacer-right"),
 				la.Width(la.Grow(1)),
-				la.Height(la.Grow(1)),
-				la.Children(
-					keyboardNode(),
-				),
 			),
 		),
 	)
+}
+
+func CreateLayout(m Metrics) *la.OutputItem {
+	var root *la.NodeItem
+
+	if m.Landscape {
+		root = la.Node(
+			la.Id("root"),
+			la.Gap(m.Padding),
+			la.Padding(m.Padding),
+			la.Width(la.Fix(m.W)),
+			la.Height(la.Fix(m.H)),
+			la.Row(),
+			la.Children(
+				la.Node(
+					la.Id("board"),
+					la.Column(),
+					la.Gap(m.Padding),
+					la.Width(la.Grow(45)),
+					la.Height(la.Grow(1)),
+					la.Children(
+						headerNode(m),
+						attemptsNode(m),
+					),
+				),
+				la.Node(
+					la.Id("bottom"),
+					la.Column(),
+					la.Width(la.Grow(55)),
+					la.Height(la.Grow(1)),
+					la.Children(
+						la.Node(la.Id("bottom-spacer-top"), la.Height(la.Grow(1))),
+						keyboardNode(m),
+						la.Node(la.Id("bottom-spacer-bottom"), la.Height(la.Grow(1))),
+					),
+				),
+			),
+		)
+	} else {
+		root = la.Node(
+			la.Id("root"),
+			la.Gap(m.Padding),
+			la.Padding(m.Padding),
+			la.Width(la.Fix(m.W)),
+			la.Height(la.Fix(m.H)),
+			la.Column(),
+			la.Children(
+				headerNode(m),
+				attemptsNode(m),
+				la.Node(
+					la.Id("bottom"),
+					la.Width(la.Grow(1)),
+					la.Height(la.Grow(1)),
+					la.Children(
+						keyboardNode(m),
+					),
+				),
+			),
+		)
+	}
 
 	la.Layout(root)
 
@@ -215,37 +367,121 @@ func CreateLayout() *la.OutputItem {
 	return node
 }
 
+// Resize rebuilds the layout for a screen of w×h device pixels at the
+// current Scale. Hover and focus move to the same keys in the new layout;
+// pointers held across a resize are dropped, since the key under them may
+// have moved.
+func (g *Game) Resize(w, h int) {
+	g.Width, g.Height = w, h
+	g.Metrics = NewMetrics(float32(w), float32(h), float32(g.Scale))
+	g.Node = CreateLayout(g.Metrics)
+	g.SettingsNode = CreateSettingsLayout(g.Metrics)
+
+	if g.Hovered != nil {
+		g.Hovered = FindNode(g.Node, g.Hovered.Id)
+	}
+
+	if g.Focused != nil {
+		g.Focused = FindNode(g.Node, g.Focused.Id)
+	}
+
+	clear(g.Pointers)
+}
+
 func (g *Game) Draw(screen *ebiten.Image) {
-	vector.FillRect(screen, 0, 0, screenW, screenH, pallete.BG, false)
+	fillRect(screen, 0, 0, float32(g.Width), float32(g.Height), g.Theme.Background)
 
 	switch g.Stage {
 	case GAME:
 		g.DrawNode(screen, g.Node)
 	case SCORE:
 		g.DrawScore(screen)
+	case SETTINGS:
+		g.DrawSettings(screen)
 	}
 }
 
 func (g *Game) DrawScore(screen *ebiten.Image) {
+	m := g.Metrics
 	txt := string(g.Word)
-	s := float32(5)
+	s := m.Px(5)
 	if g.IsWordGuessed() {
-		s = 8
+		s = m.Px(8)
 		txt = fmt.Sprintf("%d / %d", len(g.GuessedWords), 6)
 	}
 
-	DrawText(
-		screen,
-		txt,
-		screenW/2-(float32(utf8.RuneCountInString(txt))*(LetterWidth*s))/2,
-		screenH/2-LetterWidth*s,
-		s,
-		pallete.FG,
-	)
+	w := float32(g.Width) - m.Padding*2
+	s = FitScale(txt, w, s)
+	y := float32(g.Height) * 0.13
+
+	DrawTextAligned(screen, txt, TextBox{X: m.Padding, Y: y, W: w, Align: ALIGN_CENTER}, s, g.Theme.Foreground)
+
+	y += LetterHeight*s + m.Px(24)
+
+	lines := make([]string, 0, 2)
+	if len(g.Hints) > 0 {
+		lines = append(lines, fmt.Sprintf("подсказок: %d", len(g.Hints)))
+	}
+	lines = append(lines, g.Stats.Summary())
+
+	for _, txt := range lines {
+		box := TextBox{X: m.Padding, Y: y, W: w, Align: ALIGN_CENTER, Wrap: true}
+		hs := m.Px(3)
+
+		DrawTextAligned(screen, txt, box, hs, g.Theme.Passive)
+
+		_, h := measureLines(WrapText(txt, w, hs), hs)
+		y += h + LineGap*hs
+	}
+
+	g.DrawAnalysis(screen, y+m.Px(40))
+}
+
+// DrawAnalysis lists every guess of the finished round as
+// "guess before>after best skill%".
+func (g *Game) DrawAnalysis(screen *ebiten.Image, y float32) {
+	m := g.Metrics
+	w := float32(g.Width) - m.Padding*2
+	s := m.Px(3)
+
+	if g.Analysis == nil {
+		DrawTextAligned(screen, "...", TextBox{X: m.Padding, Y: y, W: w, Align: ALIGN_CENTER}, s, g.Theme.Passive)
+		return
+	}
+
+	rows := make([]string, len(g.Analysis))
+	for i, a := range g.Analysis {
+		rows[i] = fmt.Sprintf(
+			"%s %4d>%-4d %s %3d%%",
+			string(a.Guess),
+			a.Before,
+			a.Remaining,
+			string(a.Best),
+			a.Skill,
+		)
+	}
+
+	s = FitScale(strings.Join(rows, "\n"), w, s)
+	rowH := LetterHeight*s + m.Px(24)
+
+	for i, a := range g.Analysis {
+		box := TextBox{X: m.Padding, Y: y + float32(i)*rowH, W: w, Align: ALIGN_CENTER}
+		DrawTextAligned(screen, rows[i], box, s, getColorBySkill(g.Theme, a.Skill))
+	}
+}
+
+func getColorBySkill(t *pallete.Theme, skill int) color.Color {
+	switch {
+	case skill >= 90:
+		return t.Match
+	case skill >= 50:
+		return t.Foreground
+	}
+	return t.Present
 }
 
 func (g *Game) DrawKey(screen *ebiten.Image, node *la.OutputItem) {
-	c := pallete.PASSIVE
+	c := g.Theme.Passive
 
 	tmp := strings.Replace(node.Id, "key_", "", 1)
 
@@ -255,73 +491,79 @@ func (g *Game) DrawKey(screen *ebiten.Image, node *la.OutputItem) {
 		id = v
 	}
 
+	fg := g.Theme.Foreground
+
+	status := PENDING
+
 	if g.IsLetterGuessed(id) {
 		if g.IsLetterInWord(id) {
-			c = pallete.PRESENT
+			c = g.Theme.Present
+			status = PRESENT
 		} else {
-			c = pallete.MISS
+			c = g.Theme.Miss
 		}
+	} else if g.IsLetterEliminated(id) {
+		c = g.Theme.Miss
+		fg = g.Theme.Passive
+	}
+
+	if (id == '?' || id == '!') && g.HintsLeft() <= 0 {
+		c = g.Theme.Miss
+		fg = g.Theme.Passive
 	}
 
-	vector.FillRect(
+	fillRect(
 		screen,
 		node.X,
 		node.Y,
 		node.W,
 		node.H,
 		c,
-		false,
 	)
 
-	s := float32(4)
+	g.DrawStatusMarker(screen, node.X, node.Y, node.W, node.H, status)
 
-	x := node.X + (node.W / 2) - ((LetterWidth * s) / 2)
-	y := node.Y + (node.H / 2) - ((LetterWidth * s) / 2)
+	s := letterScale(node.H)
 
 	if id == '-' {
-		DrawBitmap(
+		drawGlyph(
 			screen,
-			backspaceMap,
-			x,
-			y,
+			iconFont,
+			'-',
+			node.X+(node.W-9*s)/2,
+			node.Y+(node.H-9*s)/2,
 			s,
-			9,
-			pallete.FG,
+			g.Theme.Foreground,
 		)
 	} else if id == '+' {
 		txt := "enter"
-		s := float32(1.5)
-		x := node.X + (node.W / 2) - (float32(len(txt))*LetterWidth*s)/2
-		y := node.Y + (node.H / 2) - (LetterWidth*2)/2
-		DrawText(
-			screen,
-			"enter",
-			x,
-			y,
-			s,
-			pallete.FG,
-		)
+		s := FitScale(txt, node.W*0.8, max(1, floor(s*0.375)))
+		DrawTextAligned(screen, txt, CenteredIn(node.X, node.Y, node.W, node.H), s, g.Theme.Foreground)
 	} else {
-		DrawLetter(
+		DrawTextAligned(screen, string(id), CenteredIn(node.X, node.Y, node.W, node.H), s, fg)
+	}
+
+	if g.IsLetterRevealed(id) {
+		strokeRect(
 			screen,
-			id,
-			x,
-			y,
-			s,
-			pallete.FG,
+			node.X,
+			node.Y,
+			node.W,
+			node.H,
+			g.Metrics.Px(2),
+			g.Theme.Match,
 		)
 	}
 
-	if g.Hovered != nil && g.Hovered.Id == node.Id {
-		vector.StrokeRect(
+	if g.IsHovered(node) || g.IsFocused(node) || g.IsKeyPressed(node) {
+		strokeRect(
 			screen,
 			node.X,
 			node.Y,
 			node.W,
 			node.H,
-			2,
-			pallete.FG,
-			false,
+			g.Metrics.Px(2),
+			g.Theme.Focus,
 		)
 	}
 }
@@ -333,65 +575,140 @@ func (g *Game) DrawAttemptItem(screen *ebiten.Image, node *la.OutputItem) {
 	y := node.Y
 
 	if r == len(g.GuessedWords)-1 && g.ShakeTimer > 0 {
-		d := float32(ShakeSpeed)
+		d := g.Metrics.Px(ShakeSpeed)
 
 		if g.ShakeTimer > ShakeValue/2 {
 			d *= -1
 		}
 
 		x += d
-		g.ShakeTimer -= ShakeSpeed
+		g.ShakeTimer -= ShakeSpeed * g.AnimationFactor()
 	}
 
-	vector.StrokeRect(
+	revealed, isRevealed := g.RevealedLetter(i)
+	isRevealed = isRevealed && r == g.CurrentRow()
+
+	border := g.Theme.Border
+	if isRevealed {
+		border = g.Theme.Match
+	}
+
+	strokeRect(
 		screen,
 		x,
 		y,
 		node.W,
 		node.H,
-		2,
-		pallete.FG,
-		false,
+		g.Metrics.Px(2),
+		border,
 	)
 
-	if r > len(g.GuessedWords)-1 {
-		return
+	s := letterScale(node.H)
+
+	var w []rune
+	if r < len(g.GuessedWords) {
+		w = g.GuessedWords[r]
 	}
 
-	w := g.GuessedWords[r]
+	stroke := g.Metrics.Px(2)
+	inset := g.Metrics.Px(4)
+
+	editable := g.IsEditable(r)
+	hovered := editable && (g.IsHovered(node) || g.IsKeyPressed(node))
 
 	if i > len(w)-1 {
+		if hovered {
+			strokeRect(screen, x+inset, y+inset, node.W-inset*2, node.H-inset*2, stroke, g.Theme.Passive)
+		}
+		if isRevealed {
+			DrawTextAligned(screen, string(revealed), CenteredIn(x, y, node.W, node.H), s, g.Theme.Match)
+		}
 		return
 	}
 
 	status := g.GetLetterStatus(r, i, w[i])
 
-	c := getColorByStatus(status)
+	c := getColorByStatus(g.Theme, status)
 
-	vector.FillRect(screen, x, y, node.W, node.H, c, false)
+	fillRect(screen, x, y, node.W, node.H, c)
 
-	s := float32(4)
+	g.DrawStatusMarker(screen, x, y, node.W, node.H, status)
 
-	DrawLetter(
-		screen,
-		w[i],
-		x+(attemptItemSide/2)-((LetterWidth*s)/2),
-		y+(attemptItemSide/2)-((LetterWidth*s)/2),
-		s,
-		pallete.FG,
-	)
+	if hovered {
+		strokeRect(screen, x+inset, y+inset, node.W-inset*2, node.H-inset*2, stroke, g.Theme.Passive)
+	}
+
+	// The cursor only shows while it sits on a typed letter; at the end of
+	// the row typing appends as usual.
+	if editable && i == g.Cursor {
+		fillRect(screen, x+inset*2, y+node.H-inset*2, node.W-inset*4, g.Metrics.Px(3), g.Theme.Foreground)
+	}
+
+	if isRevealed {
+		strokeRect(screen, x, y, node.W, node.H, stroke, g.Theme.Match)
+	}
+
+	DrawTextAligned(screen, string(w[i]), CenteredIn(x, y, node.W, node.H), s, g.Theme.Foreground)
 }
 
-func getColorByStatus(status LetterStatus) color.Color {
+// DrawStatusMarker marks a tile or key in colour-blind mode so its status
+// does not rest on colour alone: a corner dot for a letter in place and an
+// underline for a letter elsewhere in the word.
+func (g *Game) DrawStatusMarker(screen *ebiten.Image, x, y, w, h float32, status LetterStatus) {
+	if !g.Settings.ColorBlind {
+		return
+	}
+
+	m := g.Metrics
+	inset := m.Px(4)
+
 	switch status {
 	case GUESSED:
-		return pallete.MATCH
+		side := m.Px(6)
+		fillRect(screen, x+w-inset-side, y+inset, side, side, g.Theme.Foreground)
 	case PRESENT:
-		return pallete.PRESENT
+		fillRect(screen, x+inset*2, y+h-inset-m.Px(3), w-inset*4, m.Px(3), g.Theme.Foreground)
+	}
+}
+
+// IsEditable reports whether row r is the one being typed into.
+func (g *Game) IsEditable(r int) bool {
+	return r == g.CurrentRow() && r > g.LastSubmitted
+}
+
+func (g *Game) IsHovered(node *la.OutputItem) bool {
+	return g.Hovered != nil && g.Hovered.Id == node.Id
+}
+
+func (g *Game) IsFocused(node *la.OutputItem) bool {
+	return g.Focused != nil && g.Focused.Id == node.Id
+}
+
+func (g *Game) IsKeyPressed(node *la.OutputItem) bool {
+	for _, p := range g.Pointers {
+		if p.Node.Id == node.Id && p.IsOverNode() {
+			return true
+		}
+	}
+	return false
+}
+
+func getColorByStatus(t *pallete.Theme, status LetterStatus) color.Color {
+	switch status {
+	case GUESSED:
+		return t.Match
+	case PRESENT:
+		return t.Present
 	case WRONG:
-		return pallete.MISS
+		return t.Miss
 	}
-	return pallete.PASSIVE
+	return t.Passive
+}
+
+// letterScale is the largest whole scale that fits a letter in a tile or
+// key of the given side with some margin.
+func letterScale(side float32) float32 {
+	return max(1, float32(int(side/13)))
 }
 
 func (g *Game) DrawHeader(screen *ebiten.Image, node *la.OutputItem) {
@@ -399,17 +716,10 @@ func (g *Game) DrawHeader(screen *ebiten.Image, node *la.OutputItem) {
 	if v < 0 {
 		v = 0
 	}
-	s := float32(4)
+	s := g.Metrics.Px(4)
 
 	txt := fmt.Sprintf("%d / %d", v, 6)
-	DrawText(
-		screen,
-		txt,
-		node.X+node.W/2-(float32(len(txt))*LetterWidth*s)/2,
-		node.Y+node.Y/2+(LetterWidth*s)/2,
-		s,
-		pallete.FG,
-	)
+	DrawTextAligned(screen, txt, CenteredIn(node.X, node.Y, node.W, node.H), s, g.Theme.Foreground)
 }
 
 func (g *Game) DrawNode(screen *ebiten.Image, node *la.OutputItem) {
@@ -426,6 +736,24 @@ func (g *Game) DrawNode(screen *ebiten.Image, node *la.OutputItem) {
 	}
 }
 
-func (g *Game) Layout(a, b int) (int, int) {
-	return screenW, screenH
+// Layout makes the screen as large as the window in device pixels, so on a
+// HiDPI display nothing is upscaled and glyph pixels stay sharp.
+func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
+	scale := g.ScaleFactor()
+	w := int(math.Ceil(float64(outsideWidth) * scale))
+	h := int(math.Ceil(float64(outsideHeight) * scale))
+
+	if w > 0 && h > 0 && (w != g.Width || h != g.Height || scale != g.Scale) {
+		g.Scale = scale
+		g.Resize(w, h)
+	}
+
+	return g.Width, g.Height
+}
+
+func DeviceScaleFactor() float64 {
+	if m := ebiten.Monitor(); m != nil {
+		return m.DeviceScaleFactor()
+	}
+	return 1
 }
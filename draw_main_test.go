@@ -0,0 +1,59 @@
+//go:build draw
+
+package main
+
+import (
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden PNGs in testdata/golden")
+
+// testLoop keeps an ebiten loop alive while the tests run, so offscreen
+// images can be drawn and read back. The tests themselves never depend on
+// the window it opens.
+type testLoop struct {
+	m    *testing.M
+	code int
+	done chan struct{}
+	once bool
+}
+
+func (l *testLoop) Update() error {
+	if !l.once {
+		l.once = true
+		go func() {
+			l.code = l.m.Run()
+			close(l.done)
+		}()
+	}
+
+	select {
+	case <-l.done:
+		return ebiten.Termination
+	default:
+		return nil
+	}
+}
+
+func (l *testLoop) Draw(screen *ebiten.Image) {}
+
+func (l *testLoop) Layout(a, b int) (int, int) {
+	return 1, 1
+}
+
+func TestMain(m *testing.M) {
+	l := &testLoop{m: m, done: make(chan struct{})}
+
+	ebiten.SetWindowSize(1, 1)
+
+	err := ebiten.RunGameWithOptions(l, &ebiten.RunGameOptions{InitUnfocused: true})
+	if err != nil {
+		panic(err)
+	}
+
+	os.Exit(l.code)
+}
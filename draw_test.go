@@ -0,0 +1,150 @@
+//go:build draw
+
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestDrawSnapshots(t *testing.T) {
+	cases := []struct {
+		name  string
+		setup func(h *harness)
+	}{
+		{"empty", func(h *harness) {}},
+		{"typing", func(h *harness) {
+			h.typeRunes("саз")
+		}},
+		{"submitted", func(h *harness) {
+			h.guess("копна")
+			h.guess("сазан")
+		}},
+		{"hints", func(h *harness) {
+			h.key(ebiten.KeyF1)
+			h.key(ebiten.KeyF2)
+		}},
+		{"score", func(h *harness) {
+			h.guess("копна")
+			h.guess("вазон")
+			h.waitForAnalysis()
+		}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := newHarness(t, DAILY, "вазон")
+			c.setup(h)
+
+			screen := ebiten.NewImage(screenW, screenH)
+			h.g.Draw(screen)
+
+			checkGolden(t, c.name, screen)
+		})
+	}
+}
+
+// checkGolden compares img with testdata/golden/<name>.png. Run the tests
+// with -tags draw -update to accept a new rendering.
+func checkGolden(t *testing.T, name string, img *ebiten.Image) {
+	t.Helper()
+
+	got := image.NewRGBA(img.Bounds())
+	img.ReadPixels(got.Pix)
+
+	path := filepath.Join("testdata", "golden", name+".png")
+
+	if *update {
+		if err := writePNG(path, got); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		t.Fatalf("%s is missing, run go test -tags draw -update to create it", path)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	decoded, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := image.NewRGBA(decoded.Bounds())
+	for y := range want.Rect.Dy() {
+		for x := range want.Rect.Dx() {
+			want.Set(x, y, decoded.At(x, y))
+		}
+	}
+
+	if want.Rect != got.Rect || !bytes.Equal(want.Pix, got.Pix) {
+		dir, err := os.MkdirTemp("", "five-letters-golden")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		actual := filepath.Join(dir, name+".png")
+		if err := writePNG(actual, got); err != nil {
+			t.Fatal(err)
+		}
+		t.Fatalf("rendering differs from %s, got %s", path, actual)
+	}
+}
+
+func writePNG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return png.Encode(f, img)
+}
+
+func TestColorBlindMarkers(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	h.guess("вазон")
+	h.g.Stage = GAME
+
+	tile := FindNode(h.g.Node, "attempt_0_0")
+	dot := image.Pt(int(tile.X+tile.W)-6, int(tile.Y)+6)
+
+	screen := ebiten.NewImage(screenW, screenH)
+	h.g.Draw(screen)
+	if got := pixelAt(screen, dot); got != h.g.Theme.Match {
+		t.Fatalf("without colour-blind mode the corner is %v, want the tile colour", got)
+	}
+
+	h.key(ebiten.KeyF4)
+	if h.g.Theme != h.g.Theme.ColorBlind() || !h.g.Settings.ColorBlind {
+		t.Fatal("F4 did not switch to the colour-blind palette")
+	}
+
+	screen.Clear()
+	h.g.Draw(screen)
+	if got := pixelAt(screen, dot); got != h.g.Theme.Foreground {
+		t.Fatalf("colour-blind corner is %v, want a marker", got)
+	}
+}
+
+func pixelAt(img *ebiten.Image, p image.Point) color.RGBA {
+	pix := image.NewRGBA(img.Bounds())
+	img.ReadPixels(pix.Pix)
+	return pix.RGBAAt(p.X, p.Y)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"math"
+	"strings"
+
+	la "github.com/laranatech/gorana/layout"
+)
+
+// KeyboardRows returns the on-screen keys row by row, spacers left out.
+func KeyboardRows(root *la.OutputItem) [][]*la.OutputItem {
+	rows := make([][]*la.OutputItem, 0, 3)
+
+	var walk func(node *la.OutputItem)
+	walk = func(node *la.OutputItem) {
+		if !strings.HasPrefix(node.Id, "keyboard_row_") {
+			for _, child := range node.Children {
+				walk(child)
+			}
+			return
+		}
+
+		row := make([]*la.OutputItem, 0, len(node.Children))
+		for _, child := range node.Children {
+			if strings.HasPrefix(child.Id, "key_") {
+				row = append(row, child)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	walk(root)
+
+	return rows
+}
+
+// MoveFocus moves the keyboard focus one key in direction d. Left and right
+// wrap within a row; up and down pick the key closest by x in the next row;
+// the row jumps go to the first key of the previous or next row.
+func (g *Game) MoveFocus(d Direction) {
+	rows := KeyboardRows(g.Node)
+	if len(rows) == 0 {
+		return
+	}
+
+	r, i := findKey(rows, g.Focused)
+	if r < 0 {
+		g.Focused = rows[0][0]
+		return
+	}
+
+	switch d {
+	case NAV_LEFT:
+		i = (i - 1 + len(rows[r])) % len(rows[r])
+	case NAV_RIGHT:
+		i = (i + 1) % len(rows[r])
+	case NAV_UP, NAV_DOWN:
+		next := r - 1
+		if d == NAV_DOWN {
+			next = r + 1
+		}
+		next = (next + len(rows)) % len(rows)
+		i = closestKey(rows[next], rows[r][i])
+		r = next
+	case NAV_PREV_ROW:
+		r, i = (r-1+len(rows))%len(rows), 0
+	case NAV_NEXT_ROW:
+		r, i = (r+1)%len(rows), 0
+	}
+
+	g.Focused = rows[r][i]
+}
+
+func findKey(rows [][]*la.OutputItem, node *la.OutputItem) (int, int) {
+	if node == nil {
+		return -1, -1
+	}
+
+	for r, row := range rows {
+		for i, key := range row {
+			if key.Id == node.Id {
+				return r, i
+			}
+		}
+	}
+
+	return -1, -1
+}
+
+// closestKey returns the index of the key in row whose centre is closest by
+// x to the centre of from. On equal distance the leftmost key wins.
+func closestKey(row []*la.OutputItem, from *la.OutputItem) int {
+	cx := from.X + from.W/2
+	best := 0
+	bestD := float32(math.Inf(1))
+
+	for i, key := range row {
+		d := key.X + key.W/2 - cx
+		if d < 0 {
+			d = -d
+		}
+		if d < bestD {
+			best = i
+			bestD = d
+		}
+	}
+
+	return best
+}
+
+// KeyRune returns the input a key node stands for.
+func KeyRune(node *la.OutputItem) rune {
+	tmp := strings.ReplaceAll(node.Id, "key_", "")
+	return []rune(tmp)[0]
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//go:embed fonts/*.bdf
+var fontFiles embed.FS
+
+// TextFont is the font DrawText and DrawLetter use.
+var TextFont = mustLoadFont("pixel")
+
+var ErrBadFont = errors.New("font: malformed BDF")
+
+// Glyph is one character of a bitmap font. Bits holds Width×Height pixels
+// row by row, 1 for a lit one. X and Y place the bitmap relative to the top
+// left of the character cell and Advance is how far the pen moves after it.
+type Glyph struct {
+	Advance int
+	Width   int
+	Height  int
+	X       int
+	Y       int
+	Bits    []byte
+}
+
+// Font is a bitmap font. CellWidth and CellHeight are the size every glyph
+// fits in; the glyphs themselves may be smaller and advance by different
+// amounts.
+type Font struct {
+	Name       string
+	CellWidth  int
+	CellHeight int
+	Ascent     int
+	glyphs     map[rune]*Glyph
+	fallback   *Glyph
+}
+
+// Glyph returns the glyph for l, or the font's default character when l is
+// missing.
+func (f *Font) Glyph(l rune) *Glyph {
+	if g, ok := f.glyphs[l]; ok {
+		return g
+	}
+	return f.fallback
+}
+
+func (f *Font) HasGlyph(l rune) bool {
+	_, ok := f.glyphs[l]
+	return ok
+}
+
+// LoadFont loads one of the fonts embedded in fonts/ by name, or a BDF file
+// from disk when name is a path.
+func LoadFont(name string) (*Font, error) {
+	data, err := fontFiles.ReadFile("fonts/" + name + ".bdf")
+	if err != nil {
+		data, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return LoadBDF(bytes.NewReader(data))
+}
+
+func mustLoadFont(name string) *Font {
+	f, err := LoadFont(name)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// LoadBDF reads a font in the Glyph Bitmap Distribution Format. Only the
+// parts needed to draw glyphs are read: the bounding box, ascent, default
+// character and each glyph's encoding, advance, box and bitmap.
+func LoadBDF(r io.Reader) (*Font, error) {
+	f := &Font{glyphs: map[rune]*Glyph{}}
+	defaultChar := rune(-1)
+	descent := 0
+	boxX, boxY := 0, 0
+
+	var g *Glyph
+	var enc rune
+	bitmapRow := -1
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		if bitmapRow >= 0 {
+			if fields[0] == "ENDCHAR" {
+				if enc >= 0 {
+					f.glyphs[enc] = g
+				}
+				g, bitmapRow = nil, -1
+				continue
+			}
+
+			if bitmapRow >= g.Height {
+				return nil, ErrBadFont
+			}
+
+			row, err := strconv.ParseUint(fields[0], 16, 64)
+			if err != nil {
+				return nil, ErrBadFont
+			}
+
+			bits := len(fields[0]) * 4
+			if bits < g.Width {
+				return nil, ErrBadFont
+			}
+			for x := range g.Width {
+				g.Bits[bitmapRow*g.Width+x] = byte(row >> (bits - 1 - x) & 1)
+			}
+			bitmapRow++
+			continue
+		}
+
+		args, err := atois(fields[1:])
+
+		switch fields[0] {
+		case "FONT":
+			f.Name = strings.Join(fields[1:], " ")
+			continue
+		case "FONTBOUNDINGBOX":
+			if err != nil || len(args) < 4 {
+				return nil, ErrBadFont
+			}
+			f.CellWidth, f.CellHeight = args[0], args[1]
+			boxX, boxY = args[2], args[3]
+		case "FONT_ASCENT":
+			if err != nil || len(args) < 1 {
+				return nil, ErrBadFont
+			}
+			f.Ascent = args[0]
+		case "FONT_DESCENT":
+			if err != nil || len(args) < 1 {
+				return nil, ErrBadFont
+			}
+			descent = args[0]
+		case "DEFAULT_CHAR":
+			if err != nil || len(args) < 1 {
+				return nil, ErrBadFont
+			}
+			defaultChar = rune(args[0])
+		case "STARTCHAR":
+			g = &Glyph{Advance: f.CellWidth}
+			enc = -1
+		case "ENCODING":
+			if g == nil || err != nil || len(args) < 1 {
+				return nil, ErrBadFont
+			}
+			enc = rune(args[0])
+		case "DWIDTH":
+			if g == nil || err != nil || len(args) < 1 {
+				return nil, ErrBadFont
+			}
+			g.Advance = args[0]
+		case "BBX":
+			if g == nil || err != nil || len(args) < 4 || args[0] < 0 || args[1] < 0 {
+				return nil, ErrBadFont
+			}
+			g.Width, g.Height = args[0], args[1]
+			g.X = args[2] - boxX
+			// BDF offsets count up from the baseline, cells down from the top.
+			g.Y = f.ascent(descent, boxY) - (args[1] + args[3])
+		case "BITMAP":
+			if g == nil {
+				return nil, ErrBadFont
+			}
+			g.Bits = make([]byte, g.Width*g.Height)
+			bitmapRow = 0
+		}
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	if f.CellWidth <= 0 || f.CellHeight <= 0 || len(f.glyphs) == 0 {
+		return nil, ErrBadFont
+	}
+
+	if f.Ascent == 0 {
+		f.Ascent = f.CellHeight + boxY
+	}
+	f.CellHeight = max(f.CellHeight, f.Ascent+descent)
+
+	f.fallback = f.glyphs[defaultChar]
+	if f.fallback == nil {
+		f.fallback = &Glyph{Advance: f.CellWidth}
+	}
+
+	return f, nil
+}
+
+// ascent is FONT_ASCENT when the properties came first, as they should,
+// or the top of the bounding box otherwise.
+func (f *Font) ascent(descent, boxY int) int {
+	if f.Ascent > 0 || descent > 0 {
+		return f.Ascent
+	}
+	return f.CellHeight + boxY
+}
+
+func atois(fields []string) ([]int, error) {
+	out := make([]int, len(fields))
+	for i, s := range fields {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("font: %q is not a number", s)
+		}
+		out[i] = v
+	}
+	return out, nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testBDF = `STARTFONT 2.1
+FONT -test-narrow
+FONTBOUNDINGBOX 5 7 0 -1
+STARTPROPERTIES 2
+FONT_ASCENT 6
+FONT_DESCENT 1
+ENDPROPERTIES
+CHARS 2
+STARTCHAR i
+ENCODING 105
+DWIDTH 2 0
+BBX 1 5 0 0
+BITMAP
+80
+00
+80
+80
+80
+ENDCHAR
+STARTCHAR ru
+ENCODING 1088
+DWIDTH 5 0
+BBX 4 5 0 -1
+BITMAP
+E0
+90
+90
+E0
+80
+ENDCHAR
+ENDFONT
+`
+
+func TestLoadBDF(t *testing.T) {
+	f, err := LoadBDF(strings.NewReader(testBDF))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f.CellWidth != 5 || f.CellHeight != 7 || f.Ascent != 6 {
+		t.Fatalf("cell %dx%d ascent %d, want 5x7 ascent 6", f.CellWidth, f.CellHeight, f.Ascent)
+	}
+
+	i := f.Glyph('i')
+	if i.Advance != 2 || i.Width != 1 || i.Height != 5 || i.Y != 1 {
+		t.Fatalf("i = %+v", i)
+	}
+	if i.Bits[0] != 1 || i.Bits[1] != 0 {
+		t.Fatalf("i bits = %v", i.Bits)
+	}
+
+	r := f.Glyph('р')
+	if r.Advance != 5 || r.Y != 2 {
+		t.Fatalf("р = %+v, want advance 5 and y 2", r)
+	}
+	if got := r.Bits[4:8]; got[0] != 1 || got[3] != 1 || got[1] != 0 {
+		t.Fatalf("р row 1 = %v, want 1 0 0 1", got)
+	}
+
+	if f.HasGlyph('x') || f.Glyph('x').Advance != 5 || f.Glyph('x').Width != 0 {
+		t.Fatalf("missing glyph = %+v, want an empty cell", f.Glyph('x'))
+	}
+}
+
+func TestLoadBDFErrors(t *testing.T) {
+	for _, src := range []string{
+		"",
+		"STARTFONT 2.1\nFONTBOUNDINGBOX 8\nENDFONT\n",
+		"STARTFONT 2.1\nFONTBOUNDINGBOX 8 8 0 0\nSTARTCHAR a\nENCODING 97\nBBX 8 1 0 0\nBITMAP\nZZ\nENDCHAR\n",
+		"STARTFONT 2.1\nFONTBOUNDINGBOX 8 8 0 0\nSTARTCHAR a\nENCODING 97\nBBX 8 1 0 0\nBITMAP\n00\n00\nENDCHAR\n",
+		"STARTFONT 2.1\nFONTBOUNDINGBOX 8 8 0 0\nSTARTCHAR a\nENCODING 97\nBBX 12 1 0 0\nBITMAP\nFF\nENDCHAR\n",
+		"STARTFONT 2.1\nFONTBOUNDINGBOX 8 8 0 0\nSTARTCHAR a\nENCODING 97\nBBX -8 1 0 0\nBITMAP\nFF\nENDCHAR\n",
+		"STARTFONT 2.1\nFONTBOUNDINGBOX 8 8 0 0\nSTARTCHAR a\nENCODING 97\nBBX 8 -1 0 0\nBITMAP\nENDCHAR\n",
+	} {
+		if _, err := LoadBDF(strings.NewReader(src)); !errors.Is(err, ErrBadFont) {
+			t.Errorf("LoadBDF(%q) = %v, want ErrBadFont", src, err)
+		}
+	}
+}
+
+func TestEmbeddedFontCoversAlphabet(t *testing.T) {
+	f, err := LoadFont("pixel")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, l := range alphabet + "0123456789?!%/.>" {
+		if !f.HasGlyph(l) {
+			t.Errorf("pixel font has no %q", l)
+		}
+	}
+}
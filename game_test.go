@@ -0,0 +1,836 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	la "github.com/laranatech/gorana/layout"
+)
+
+func TestTypingAndBackspace(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	h.typeRunes("копнаб")
+	if got := h.row(0); got != "копна" {
+		t.Fatalf("row 0 = %q, want %q", got, "копна")
+	}
+
+	h.key(ebiten.KeyBackspace)
+	h.key(ebiten.KeyBackspace)
+	if got := h.row(0); got != "коп" {
+		t.Fatalf("row 0 = %q, want %q", got, "коп")
+	}
+}
+
+func TestSubmitRejectsInvalidWords(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	h.typeRunes("коп")
+	h.key(ebiten.KeyEnter)
+	if h.g.LastSubmitted != -1 || h.g.ShakeTimer == 0 {
+		t.Fatalf("short word accepted: submitted %d, shake %d", h.g.LastSubmitted, h.g.ShakeTimer)
+	}
+
+	h.typeRunes("аа")
+	h.key(ebiten.KeyEnter)
+	if h.g.LastSubmitted != -1 {
+		t.Fatalf("unknown word %q accepted", h.row(0))
+	}
+}
+
+func TestSubmitScoresGuess(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	h.guess("сазан")
+
+	want := []LetterStatus{WRONG, GUESSED, GUESSED, PRESENT, GUESSED}
+	for i, l := range h.g.GuessedWords[0] {
+		if got := h.g.GetLetterStatus(0, i, l); got != want[i] {
+			t.Errorf("letter %d status = %d, want %d", i, got, want[i])
+		}
+	}
+
+	if h.g.Stage != GAME || h.g.LastSubmitted != 0 || len(h.g.GuessedWords) != 2 {
+		t.Fatalf("unexpected state after submit: stage %d, submitted %d, rows %d",
+			h.g.Stage, h.g.LastSubmitted, len(h.g.GuessedWords))
+	}
+
+	if !h.g.IsLetterInWord('а') || h.g.IsLetterInWord('с') {
+		t.Fatal("keyboard letter state does not follow feedback")
+	}
+}
+
+func TestWinShowsAnalysis(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	h.guess("копна")
+	h.guess("вазон")
+
+	if h.g.Stage != SCORE || !h.g.IsWordGuessed() {
+		t.Fatalf("stage = %d, guessed = %v", h.g.Stage, h.g.IsWordGuessed())
+	}
+
+	h.waitForAnalysis()
+
+	if len(h.g.Analysis) != 2 {
+		t.Fatalf("analysis has %d rows, want 2", len(h.g.Analysis))
+	}
+
+	last := h.g.Analysis[1]
+	if last.Remaining != 1 || last.Before < last.Remaining {
+		t.Fatalf("last guess analysis = %+v", last)
+	}
+}
+
+func TestHintsNarrowAnalysis(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	h.guess("копна")
+	h.key(ebiten.KeyF1)
+	if got := h.g.Hints[0].Turn; got != 1 {
+		t.Fatalf("hint taken after one guess has turn %d, want 1", got)
+	}
+	h.guess("вазон")
+
+	h.waitForAnalysis()
+
+	plain := Analyze(h.g.GuessedWords, h.g.Word, nil)
+	if h.g.Analysis[0].Before != plain[0].Before {
+		t.Fatalf("hint narrowed the guess before it: %d, want %d", h.g.Analysis[0].Before, plain[0].Before)
+	}
+
+	hinted := FilterHint(SolverWords(), h.g.Hints[0])
+	want := len(Filter(hinted, newSolverWord([]rune("копна")), h.g.Feedback[0]))
+	if got := h.g.Analysis[1].Before; got != want || got >= plain[1].Before {
+		t.Fatalf("second guess faced %d candidates, want %d (without the hint %d)", got, want, plain[1].Before)
+	}
+}
+
+func TestSixMissesEndRound(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	for range 6 {
+		h.guess("копна")
+	}
+
+	if h.g.Stage != SCORE || h.g.IsWordGuessed() {
+		t.Fatalf("stage = %d, guessed = %v", h.g.Stage, h.g.IsWordGuessed())
+	}
+}
+
+func TestMouseAndTouchPressKeys(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	h.click("key_с")
+	h.tap("key_а")
+	h.click("key_з")
+	h.tap("key_а")
+	h.click("key_н")
+	h.click("key_-")
+	h.tap("key_н")
+	h.click("key_+")
+
+	if got := h.row(0); got != "сазан" {
+		t.Fatalf("row 0 = %q, want %q", got, "сазан")
+	}
+
+	if h.g.LastSubmitted != 0 {
+		t.Fatal("enter key did not submit")
+	}
+}
+
+func TestHints(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	h.key(ebiten.KeyF1)
+	if l, ok := h.g.RevealedLetter(0); !ok || l != 'в' {
+		t.Fatalf("revealed %q, %v; want 'в'", l, ok)
+	}
+
+	h.key(ebiten.KeyF2)
+	eliminated := h.g.Hints[1].Letters
+	if len(eliminated) != EliminateCount {
+		t.Fatalf("eliminated %d letters, want %d", len(eliminated), EliminateCount)
+	}
+	for _, l := range eliminated {
+		if slices.Contains(h.g.Word, l) {
+			t.Fatalf("eliminated letter %q is in the word", l)
+		}
+	}
+
+	h.key(ebiten.KeyF1)
+	h.key(ebiten.KeyF1)
+	if len(h.g.Hints) != MaxHints {
+		t.Fatalf("%d hints recorded, want %d", len(h.g.Hints), MaxHints)
+	}
+}
+
+func TestAbsurdleDodgesGuesses(t *testing.T) {
+	h := newHarness(t, ABSURDLE, "")
+
+	h.guess("вазон")
+
+	if h.g.IsWordGuessed() {
+		t.Fatal("adversary let the first guess win")
+	}
+
+	for _, c := range h.g.Candidates {
+		if Score(newSolverWord([]rune("вазон")), c) != h.g.Feedback[0] {
+			t.Fatalf("candidate %q contradicts shown feedback", string(c.runes[:]))
+		}
+	}
+
+	if h.g.HintsLeft() != 0 {
+		t.Fatal("hints offered without a fixed word")
+	}
+}
+
+func TestReplayRoundTrip(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	path := filepath.Join(t.TempDir(), "round.5lr")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := h.g.StartRecording(f); err != nil {
+		t.Fatal(err)
+	}
+
+	h.guess("копна")
+	h.wait(time.Second)
+	h.guess("вазон")
+
+	r, err := LoadReplay(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(r.Word) != "вазон" || len(r.Events) != 12 {
+		t.Fatalf("replay word %q with %d events", string(r.Word), len(r.Events))
+	}
+
+	p := newHarness(t, DAILY, "")
+	p.attach(NewPlayback(r))
+
+	p.wait(r.Events[len(r.Events)-1].At + tick)
+
+	if p.g.Stage != SCORE || p.row(0) != "копна" || p.row(1) != "вазон" {
+		t.Fatalf("playback ended in stage %d with rows %q, %q", p.g.Stage, p.row(0), p.row(1))
+	}
+}
+
+func TestReplayKeepsAbandonedRound(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	var out bytes.Buffer
+	if err := h.g.StartRecording(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	h.guess("копна")
+	h.wait(time.Second)
+	h.typeRunes("ваз")
+
+	r := &Replay{}
+	if err := r.UnmarshalBinary(out.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Events) != 9 || r.Events[8].Input != 'з' {
+		t.Fatalf("recorded %d events so far, want the 9 typed", len(r.Events))
+	}
+}
+
+func TestReplayRejectsCorruptFiles(t *testing.T) {
+	r := NewReplay(DAILY, []rune("вазон"))
+	r.Events = append(r.Events, ReplayEvent{At: time.Second, Input: 'ж'})
+	good, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bad := map[string][]byte{
+		"mode":      append([]byte(replayMagic+"\x07"), good[len(replayMagic)+1:]...),
+		"flags":     []byte(replayMagic + "\x00\x80вазон\n"),
+		"short":     []byte(replayMagic + "\x00\x00ваз\n"),
+		"long":      []byte(replayMagic + "\x00\x00вазоны\n"),
+		"no word":   []byte(replayMagic + "\x00\x00вазон"),
+		"truncated": good[:len(good)-1],
+	}
+
+	for name, data := range bad {
+		if err := (&Replay{}).UnmarshalBinary(data); err != ErrBadReplay {
+			t.Errorf("%s: got %v, want ErrBadReplay", name, err)
+		}
+	}
+
+	v1 := append([]byte(replayMagicV1+"\x01вазон\n"), good[len(replayMagic)+2+len("вазон\n"):]...)
+	if err := r.UnmarshalBinary(v1); err != nil || r.Mode != ABSURDLE || r.HardMode || len(r.Events) != 1 {
+		t.Fatalf("version 1 file read as %+v, %v", r, err)
+	}
+}
+
+func TestReplayHardMode(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	var out bytes.Buffer
+	if err := h.g.StartRecording(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	h.g.ChangeSetting(SETTING_HARD_MODE, 1)
+	h.guess("сазан")
+	h.guess("копна")
+	h.key(ebiten.KeyEscape)
+	h.guess("вазон")
+
+	r := &Replay{}
+	if err := r.UnmarshalBinary(out.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if r.HardMode {
+		t.Fatal("the round started with hard mode off")
+	}
+
+	p := newHarness(t, DAILY, "")
+	p.attach(NewPlayback(r))
+	p.wait(r.Events[len(r.Events)-1].At + tick)
+
+	if p.g.Stage != SCORE || p.row(1) != "вазон" {
+		t.Fatalf("playback ended in stage %d with row 1 %q; hard mode should reject копна", p.g.Stage, p.row(1))
+	}
+
+	h = newHarness(t, DAILY, "вазон")
+	h.g.Settings.HardMode = true
+	out.Reset()
+	if err := h.g.StartRecording(&out); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.UnmarshalBinary(out.Bytes()); err != nil || !r.HardMode {
+		t.Fatalf("hard mode not kept in the header: %+v, %v", r, err)
+	}
+	if !NewPlayback(r).Settings.HardMode {
+		t.Fatal("playback of a hard mode round is not in hard mode")
+	}
+}
+
+func TestScriptedEvents(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	key := FindNode(h.g.Node, "key_з")
+	down := Event{Kind: EVENT_POINTER_DOWN, X: key.X + 1, Y: key.Y + 1}
+
+	h.script(
+		[]Event{RuneEvent('с'), RuneEvent('а'), RuneEvent('х')},
+		[]Event{{Kind: EVENT_BACKSPACE}},
+		[]Event{down, {Kind: EVENT_POINTER_UP, X: down.X, Y: down.Y}},
+		[]Event{RuneEvent('а'), RuneEvent('н'), {Kind: EVENT_SUBMIT}},
+	)
+
+	if got := h.row(0); got != "сазан" || h.g.LastSubmitted != 0 {
+		t.Fatalf("row 0 = %q, submitted %d", got, h.g.LastSubmitted)
+	}
+}
+
+func TestGamepadDeletesAndSubmits(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	h.typeRunes("сазак")
+	h.button(ebiten.StandardGamepadButtonRightRight)
+	h.typeRunes("н")
+	h.button(ebiten.StandardGamepadButtonCenterRight)
+
+	if got := h.row(0); got != "сазан" || h.g.LastSubmitted != 0 {
+		t.Fatalf("row 0 = %q, submitted %d", got, h.g.LastSubmitted)
+	}
+}
+
+func TestKeyboardLayouts(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	for _, c := range "СаЗ" {
+		h.char(c)
+	}
+	h.key(ebiten.KeyQ)
+	if got := h.row(0); got != "саз" {
+		t.Fatalf("chars layout row = %q, want %q", got, "саз")
+	}
+
+	h.g.SetInputLayout(LAYOUT_PHYSICAL)
+	h.key(ebiten.KeyF)
+	h.key(ebiten.KeyBackquote)
+	if got := h.row(0); got != "сазае" {
+		t.Fatalf("physical layout row = %q, want %q", got, "сазае")
+	}
+}
+
+func TestTransliteration(t *testing.T) {
+	cases := map[string]string{
+		"kasha": "каша",
+		"shchi": "щи",
+		"yama":  "яма",
+		"zhuk":  "жук",
+		"tsar'": "царь",
+		"koshk": "кошк",
+	}
+
+	for in, want := range cases {
+		h := newHarness(t, DAILY, "вазон")
+		h.g.SetInputLayout(LAYOUT_TRANSLIT)
+
+		for _, c := range in {
+			h.char(c)
+		}
+
+		if got := h.row(0); got != want {
+			t.Errorf("%q typed as %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTransliterationOnFullRow(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	h.g.SetInputLayout(LAYOUT_TRANSLIT)
+
+	for _, c := range "vazonsh" {
+		h.char(c)
+	}
+	if got := h.row(0); got != "вазон" {
+		t.Fatalf("sh on a full row gave %q, want the row untouched", got)
+	}
+
+	h.key(ebiten.KeyBackspace)
+	for _, c := range "sh" {
+		h.char(c)
+	}
+	if got := h.row(0); got != "вазош" {
+		t.Fatalf("sh after freeing a tile gave %q, want %q", got, "вазош")
+	}
+}
+
+func TestTransliterationAfterMovingCursor(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	var out bytes.Buffer
+	if err := h.g.StartRecording(&out); err != nil {
+		t.Fatal(err)
+	}
+	h.typeRunes("сазан")
+	h.g.SetInputLayout(LAYOUT_TRANSLIT)
+
+	h.tap("attempt_0_0")
+	for _, c := range "sh" {
+		h.char(c)
+	}
+	if got := h.row(0); got != "шазан" {
+		t.Fatalf("sh over tile 0 gave %q, want %q", got, "шазан")
+	}
+	if h.g.Cursor != 1 {
+		t.Fatalf("cursor = %d, want 1", h.g.Cursor)
+	}
+
+	h.tap("attempt_0_4")
+	for _, c := range "shch" {
+		h.char(c)
+	}
+	if got := h.row(0); got != "шазащ" {
+		t.Fatalf("shch over tile 4 gave %q, want %q", got, "шазащ")
+	}
+
+	r := &Replay{}
+	if err := r.UnmarshalBinary(out.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	p := newHarness(t, DAILY, "")
+	p.attach(NewPlayback(r))
+	p.wait(r.Events[len(r.Events)-1].At + tick)
+	if got := p.row(0); got != "шазащ" {
+		t.Fatalf("playback typed %q, want %q", got, "шазащ")
+	}
+}
+
+func TestKeyboardFocusNavigation(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	h.key(ebiten.KeyArrowRight)
+	if h.g.Focused == nil || h.g.Focused.Id != "key_й" {
+		t.Fatalf("first arrow focused %v, want key_й", h.g.Focused)
+	}
+
+	h.key(ebiten.KeyArrowLeft)
+	h.key(ebiten.KeyArrowDown)
+	h.key(ebiten.KeyArrowDown)
+	if h.g.Focused.Id != "key_-" {
+		t.Fatalf("focused %s, want key_-", h.g.Focused.Id)
+	}
+
+	h.key(ebiten.KeyArrowLeft)
+	if h.g.Focused.Id != "key_ю" {
+		t.Fatalf("focused %s, want key_ю", h.g.Focused.Id)
+	}
+
+	h.key(ebiten.KeyEnter)
+	h.button(ebiten.StandardGamepadButtonLeftLeft)
+	h.button(ebiten.StandardGamepadButtonRightBottom)
+	if got := h.row(0); got != "юб" {
+		t.Fatalf("row 0 = %q, want %q", got, "юб")
+	}
+
+	h.char('а')
+	if h.g.Focused != nil {
+		t.Fatal("typing did not clear the focus")
+	}
+}
+
+func TestClosestKeyPrefersLeftOnTie(t *testing.T) {
+	row := []*la.OutputItem{
+		{Id: "key_а", X: 0, W: 10},
+		{Id: "key_б", X: 20, W: 10},
+		{Id: "key_в", X: 40, W: 10},
+	}
+
+	if got := closestKey(row, &la.OutputItem{X: 10, W: 10}); got != 0 {
+		t.Fatalf("midway between а and б picked %s, want key_а", row[got].Id)
+	}
+	if got := closestKey(row, &la.OutputItem{X: 31, W: 10}); got != 2 {
+		t.Fatalf("just right of midway picked %s, want key_в", row[got].Id)
+	}
+
+	h := newHarness(t, DAILY, "вазон")
+	h.g.Focused = FindNode(h.g.Node, "key_ы")
+	h.key(ebiten.KeyArrowUp)
+	if h.g.Focused.Id != "key_ц" {
+		t.Fatalf("up from key_ы focused %s, want key_ц", h.g.Focused.Id)
+	}
+}
+
+func TestGamepadCursor(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	h.connectGamepad()
+	if h.g.Focused == nil || h.g.Focused.Id != "key_й" {
+		t.Fatalf("connecting a gamepad focused %v, want key_й", h.g.Focused)
+	}
+
+	h.stick(1, 0)
+	h.wait((stickRepeatDelay + 1) * tick)
+	h.stick(0, 0)
+	h.tick()
+	if h.g.Focused.Id != "key_у" {
+		t.Fatalf("held stick focused %s, want key_у", h.g.Focused.Id)
+	}
+
+	h.button(ebiten.StandardGamepadButtonFrontTopRight)
+	h.button(ebiten.StandardGamepadButtonRightBottom)
+	h.button(ebiten.StandardGamepadButtonFrontTopRight)
+	if h.g.Focused.Id != "key_+" {
+		t.Fatalf("right shoulder focused %s, want key_+", h.g.Focused.Id)
+	}
+	h.button(ebiten.StandardGamepadButtonFrontTopLeft)
+	h.button(ebiten.StandardGamepadButtonFrontTopLeft)
+	h.button(ebiten.StandardGamepadButtonRightBottom)
+	if got := h.row(0); got != "фй" {
+		t.Fatalf("row 0 = %q, want %q", got, "фй")
+	}
+
+	h.button(ebiten.StandardGamepadButtonLeftRight)
+	focused := h.g.Focused
+
+	// The pad goes away with its stick still pushed right.
+	h.stick(1, 0)
+	h.device.removed = append(h.device.removed, 0)
+	delete(h.device.buttons, 0)
+	h.wait((stickRepeatDelay + stickRepeatRate*2) * tick)
+	if h.g.Focused != focused {
+		t.Fatalf("disconnected pad moved the focus to %s, want %s", h.g.Focused.Id, focused.Id)
+	}
+	if got := h.row(0); got != "фй" {
+		t.Fatalf("row 0 = %q after the disconnect, want %q", got, "фй")
+	}
+
+	h.key(ebiten.KeyArrowRight)
+	if h.g.Focused.Id != "key_у" {
+		t.Fatalf("arrow after the disconnect focused %s, want key_у", h.g.Focused.Id)
+	}
+
+	h.stick(0, 0)
+	h.connectGamepad()
+	if h.g.Focused.Id != "key_у" {
+		t.Fatalf("reconnecting focused %s, want key_у", h.g.Focused.Id)
+	}
+}
+
+func TestKeyActivatesOnReleaseInside(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	x, y := h.center("key_к")
+	h.mouseDown(x, y)
+	h.wait(time.Second)
+	if got := h.row(0); got != "" {
+		t.Fatalf("holding the button typed %q", got)
+	}
+	h.mouseUp(x, y)
+
+	ox, oy := h.center("key_о")
+	h.mouseDown(x, y)
+	h.mouseUp(ox, oy)
+
+	h.mouseDown(ox, oy)
+	h.mouseUp(ox, oy)
+	h.mouseDown(ox, oy)
+	h.mouseUp(ox, oy)
+
+	if got := h.row(0); got != "коо" {
+		t.Fatalf("row 0 = %q, want %q", got, "коо")
+	}
+}
+
+func TestSimultaneousTouches(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	kx, ky := h.center("key_к")
+	ox, oy := h.center("key_о")
+
+	h.touchDown(1, kx, ky)
+	h.touchDown(2, ox, oy)
+	h.touchUp(2)
+	h.touchUp(1)
+
+	if got := h.row(0); got != "ок" {
+		t.Fatalf("row 0 = %q, want %q", got, "ок")
+	}
+
+	if h.g.Hovered != nil {
+		t.Fatalf("touches left %s hovered", h.g.Hovered.Id)
+	}
+}
+
+func TestSwipeOffKeyCancels(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	kx, ky := h.center("key_к")
+	ox, oy := h.center("key_о")
+	key := FindNode(h.g.Node, "key_к")
+
+	h.touchDown(1, kx, ky)
+	if !h.g.IsKeyPressed(key) {
+		t.Fatal("touched key is not shown pressed")
+	}
+
+	h.touchMove(1, ox, oy)
+	if h.g.IsKeyPressed(key) {
+		t.Fatal("key still shown pressed after sliding off")
+	}
+
+	h.touchUp(1)
+	if got := h.row(0); got != "" {
+		t.Fatalf("swipe typed %q", got)
+	}
+}
+
+func TestMouseIgnoredWhileTouching(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	kx, ky := h.center("key_к")
+	ox, oy := h.center("key_о")
+
+	h.device.touches[1] = [2]int{kx, ky}
+	h.device.touchDown = append(h.device.touchDown, 1)
+	h.mouseDown(kx, ky)
+	h.mouseUp(kx, ky)
+	h.touchUp(1)
+
+	h.mouseDown(ox, oy)
+	h.mouseUp(ox, oy)
+
+	if got := h.row(0); got != "ко" {
+		t.Fatalf("row 0 = %q, want %q", got, "ко")
+	}
+
+	if h.g.Hovered == nil || h.g.Hovered.Id != "key_о" {
+		t.Fatalf("mouse hovers %v, want key_о", h.g.Hovered)
+	}
+}
+
+func TestMouseReleasedWhileTouching(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	kx, ky := h.center("key_к")
+	ox, oy := h.center("key_о")
+
+	h.mouseDown(kx, ky)
+	h.touchDown(1, ox, oy)
+	h.mouseUp(kx, ky)
+
+	if _, ok := h.g.Pointers[MousePointer]; ok {
+		t.Fatal("mouse still pressed after its button was released")
+	}
+
+	h.touchUp(1)
+	if got := h.row(0); got != "ко" {
+		t.Fatalf("row 0 = %q, want %q", got, "ко")
+	}
+}
+
+func TestKeyRepeat(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	h.g.SetKeyRepeat(KeyRepeat{Delay: 10, Rate: 2})
+
+	h.typeRunes("сазан")
+	h.hold(ebiten.KeyBackspace, 13)
+	if got := h.row(0); got != "са" {
+		t.Fatalf("held backspace left %q, want %q", got, "са")
+	}
+
+	h.hold(ebiten.KeyEnter, 20)
+	if h.g.ShakeTimer == 0 || h.g.LastSubmitted != -1 {
+		t.Fatal("held enter was not a single rejected submit")
+	}
+
+	h.g.SetInputLayout(LAYOUT_PHYSICAL)
+	h.hold(ebiten.KeyR, 11)
+	if got := h.row(0); got != "сакк" {
+		t.Fatalf("held letter typed %q, want %q", got, "сакк")
+	}
+}
+
+func TestClearRow(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	h.guess("копна")
+	h.typeRunes("саз")
+	h.key(ebiten.KeyEscape)
+	if got := h.row(1); got != "" {
+		t.Fatalf("escape left %q", got)
+	}
+
+	h.typeRunes("саз")
+	h.device.held[ebiten.KeyControlLeft] = 0
+	h.key(ebiten.KeyBackspace)
+	delete(h.device.held, ebiten.KeyControlLeft)
+	if got := h.row(1); got != "" {
+		t.Fatalf("ctrl+backspace left %q", got)
+	}
+
+	if got := h.row(0); got != "копна" {
+		t.Fatalf("clearing touched the submitted row: %q", got)
+	}
+}
+
+func TestTapTileEditsLetter(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	h.typeRunes("сазан")
+	h.tap("attempt_0_0")
+	h.typeRunes("в")
+	if got := h.row(0); got != "вазан" || h.g.Cursor != 1 {
+		t.Fatalf("row 0 = %q, cursor %d; want %q, 1", got, h.g.Cursor, "вазан")
+	}
+
+	h.click("attempt_0_4")
+	h.key(ebiten.KeyBackspace)
+	if got := h.row(0); got != "вазн" || h.g.Cursor != 3 {
+		t.Fatalf("row 0 = %q, cursor %d; want %q, 3", got, h.g.Cursor, "вазн")
+	}
+
+	h.typeRunes("он")
+	if got := h.row(0); got != "вазон" {
+		t.Fatalf("row 0 = %q, want %q", got, "вазон")
+	}
+
+	h.tap("attempt_1_0")
+	if h.g.Cursor != 5 {
+		t.Fatalf("tapping another row moved the cursor to %d", h.g.Cursor)
+	}
+}
+
+func TestBackspaceOnNewRow(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	h.guess("сазан")
+	h.key(ebiten.KeyBackspace)
+	if got := h.row(1); got != "" || h.g.Cursor != 0 {
+		t.Fatalf("row 1 = %q, cursor %d; want an empty row", got, h.g.Cursor)
+	}
+
+	h.typeRunes("в")
+	if got := h.row(1); got != "в" || h.row(0) != "сазан" {
+		t.Fatalf("rows %q, %q after backspace on a new row", h.row(0), got)
+	}
+}
+
+func TestResizeLayout(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	h.g.Focused = FindNode(h.g.Node, "key_й")
+
+	for _, size := range [][2]int{{400, 800}, {1200, 600}, {320, 480}} {
+		w, ht := h.g.Layout(size[0], size[1])
+		if w != size[0] || ht != size[1] {
+			t.Fatalf("Layout(%d, %d) = %d, %d", size[0], size[1], w, ht)
+		}
+
+		if h.g.Focused != FindNode(h.g.Node, "key_й") {
+			t.Fatalf("%v: focus was not moved to the new layout", size)
+		}
+
+		for _, row := range KeyboardRows(h.g.Node) {
+			for _, key := range row {
+				if key.X < 0 || key.Y < 0 || key.X+key.W > float32(w) || key.Y+key.H > float32(ht) {
+					t.Fatalf("%v: %s at %v,%v %vx%v is off screen", size, key.Id, key.X, key.Y, key.W, key.H)
+				}
+			}
+		}
+
+		last := FindNode(h.g.Node, "attempt_5_4")
+		if last.X+last.W > float32(w) || last.Y+last.H > float32(ht) {
+			t.Fatalf("%v: the board does not fit", size)
+		}
+	}
+
+	h.g.Layout(1200, 600)
+	keyboard := FindNode(h.g.Node, "keyboard")
+	attempts := FindNode(h.g.Node, "attempts")
+	if keyboard.X < attempts.X+attempts.W {
+		t.Fatal("landscape layout did not put the keyboard beside the board")
+	}
+
+	h.tap("key_к")
+	h.click("key_о")
+	if got := h.row(0); got != "ко" {
+		t.Fatalf("row 0 = %q after resize, want %q", got, "ко")
+	}
+}
+
+func TestHighDPILayout(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	key := FindNode(h.g.Node, "key_к")
+	side := key.W
+
+	h.g.ScaleFactor = func() float64 { return 2 }
+	if w, ht := h.g.Layout(screenW, screenH); w != screenW*2 || ht != screenH*2 {
+		t.Fatalf("Layout at 2x = %d, %d; want %d, %d", w, ht, screenW*2, screenH*2)
+	}
+
+	key = FindNode(h.g.Node, "key_к")
+	if key.W < side*2-2 || key.W > side*2+2 {
+		t.Fatalf("key side at 2x = %v, want about %v", key.W, side*2)
+	}
+
+	tile := FindNode(h.g.Node, "attempt_0_0")
+	if key.W != float32(int(key.W)) || tile.W != float32(int(tile.W)) {
+		t.Fatalf("sides %v and %v are not whole pixels", key.W, tile.W)
+	}
+
+	if s := letterScale(tile.H); s != float32(int(s)) || s < 8 {
+		t.Fatalf("letter scale at 2x = %v", s)
+	}
+
+	h.click("key_к")
+	if got := h.row(0); got != "к" {
+		t.Fatalf("row 0 = %q after a click at 2x, want %q", got, "к")
+	}
+}
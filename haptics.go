@@ -0,0 +1,71 @@
+package main
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type Haptic byte
+
+const (
+	HAPTIC_KEY Haptic = iota
+	HAPTIC_ERROR
+	HAPTIC_WIN
+)
+
+var hapticDurations = [...]time.Duration{
+	HAPTIC_KEY:   15 * time.Millisecond,
+	HAPTIC_ERROR: 150 * time.Millisecond,
+	HAPTIC_WIN:   400 * time.Millisecond,
+}
+
+// Haptics intensities. Off and normal share their names with the animation
+// speeds.
+const (
+	HAPTICS_OFF    = "off"
+	HAPTICS_WEAK   = "weak"
+	HAPTICS_NORMAL = "normal"
+	HAPTICS_STRONG = "strong"
+)
+
+var hapticStrengths = []string{HAPTICS_OFF, HAPTICS_WEAK, HAPTICS_NORMAL, HAPTICS_STRONG}
+
+var hapticMagnitudes = map[string]float64{
+	HAPTICS_WEAK:   0.3,
+	HAPTICS_NORMAL: 0.6,
+	HAPTICS_STRONG: 1,
+}
+
+// Vibrator shakes the device for d at magnitude between 0 and 1. Tests swap
+// it for one that only records the calls.
+type Vibrator interface {
+	Vibrate(d time.Duration, magnitude float64)
+}
+
+type EbitenVibrator struct{}
+
+func (EbitenVibrator) Vibrate(d time.Duration, magnitude float64) {
+	ebiten.Vibrate(&ebiten.VibrateOptions{Duration: d, Magnitude: magnitude})
+}
+
+// DefaultVibrator vibrates on phones and in browsers, where ebiten can, and
+// is nil on desktops, so haptics quietly do nothing there.
+func DefaultVibrator() Vibrator {
+	switch runtime.GOOS {
+	case "android", "ios", "js":
+		return EbitenVibrator{}
+	}
+	return nil
+}
+
+// Vibrate plays h at the intensity chosen in the settings.
+func (g *Game) Vibrate(h Haptic) {
+	magnitude := hapticMagnitudes[g.Settings.Haptics]
+	if g.Vibrator == nil || magnitude == 0 {
+		return
+	}
+
+	g.Vibrator.Vibrate(hapticDurations[h], magnitude)
+}
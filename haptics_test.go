@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// fakeVibrator records vibrations instead of making them.
+type fakeVibrator struct {
+	durations  []time.Duration
+	magnitudes []float64
+}
+
+func (v *fakeVibrator) Vibrate(d time.Duration, magnitude float64) {
+	v.durations = append(v.durations, d)
+	v.magnitudes = append(v.magnitudes, magnitude)
+}
+
+func TestHaptics(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	vibrator := &fakeVibrator{}
+	h.g.Vibrator = vibrator
+
+	h.tap("key_в")
+	h.tap("key_+")
+	want := []time.Duration{hapticDurations[HAPTIC_KEY], hapticDurations[HAPTIC_KEY], hapticDurations[HAPTIC_ERROR]}
+	if len(vibrator.durations) != len(want) {
+		t.Fatalf("vibrated %v, want %v", vibrator.durations, want)
+	}
+	for i := range want {
+		if vibrator.durations[i] != want[i] || vibrator.magnitudes[i] != hapticMagnitudes[HAPTICS_NORMAL] {
+			t.Fatalf("vibration %d was %v at %v", i, vibrator.durations[i], vibrator.magnitudes[i])
+		}
+	}
+
+	h.typeRunes("азон")
+	vibrator.durations = nil
+	h.key(ebiten.KeyEnter)
+	if len(vibrator.durations) != 1 || vibrator.durations[0] != hapticDurations[HAPTIC_WIN] {
+		t.Fatalf("win vibrated %v", vibrator.durations)
+	}
+}
+
+func TestHapticsIntensity(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	vibrator := &fakeVibrator{}
+	h.g.Vibrator = vibrator
+
+	h.g.ChangeSetting(SETTING_HAPTICS, 1)
+	if h.g.Settings.Haptics != HAPTICS_STRONG || h.g.SettingValue(SETTING_HAPTICS) != "сильная" {
+		t.Fatalf("haptics %q, want strong", h.g.Settings.Haptics)
+	}
+	if vibrator.magnitudes[0] != 1 {
+		t.Fatalf("previewed at %v, want the new strength", vibrator.magnitudes[0])
+	}
+
+	h.g.ChangeSetting(SETTING_HAPTICS, 1)
+	vibrator.durations = nil
+	h.tap("key_в")
+	if h.g.Settings.Haptics != HAPTICS_OFF || len(vibrator.durations) != 0 {
+		t.Fatalf("haptics %q vibrated %v", h.g.Settings.Haptics, vibrator.durations)
+	}
+}
+
+func TestHapticsDesktopNoop(t *testing.T) {
+	g := NewGame(DAILY)
+	if g.Vibrator != nil {
+		t.Fatalf("desktop vibrator %T, want none", g.Vibrator)
+	}
+
+	g.Vibrate(HAPTIC_WIN)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+)
+
+// HardModeViolation explains why guess breaks hard mode, or returns "" when
+// it is allowed. In hard mode every letter found in place must stay there
+// and every letter found elsewhere must be used again.
+func (g *Game) HardModeViolation(guess []rune) string {
+	if !g.Settings.HardMode {
+		return ""
+	}
+
+	for r := 0; r <= g.LastSubmitted; r++ {
+		for i, l := range g.GuessedWords[r] {
+			if g.GetLetterStatus(r, i, l) == GUESSED && guess[i] != l {
+				return fmt.Sprintf("%d буква должна быть %c", i+1, l)
+			}
+		}
+	}
+
+	for r := 0; r <= g.LastSubmitted; r++ {
+		for i, l := range g.GuessedWords[r] {
+			if g.GetLetterStatus(r, i, l) == PRESENT && !slices.Contains(guess, l) {
+				return fmt.Sprintf("в слове должна быть буква %c", l)
+			}
+		}
+	}
+
+	return ""
+}
+
+// CanChangeHardMode reports whether hard mode may be switched now: only
+// before the first guess, so it cannot be dropped halfway through a round.
+func (g *Game) CanChangeHardMode() bool {
+	return g.LastSubmitted < 0
+}
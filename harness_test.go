@@ -0,0 +1,399 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const tick = time.Second / 60
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// fakeDevice is a scripted Device. Everything "just" pressed or released
+// lasts for a single tick, see endTick.
+type fakeDevice struct {
+	held      map[ebiten.Key]int
+	chars     []rune
+	cursorX   int
+	cursorY   int
+	mouseDown bool
+	mouseUp   bool
+	touches   map[ebiten.TouchID][2]int
+	touchDown []ebiten.TouchID
+	touchUp   []ebiten.TouchID
+	buttons   map[ebiten.GamepadID][]ebiten.StandardGamepadButton
+	axes      map[ebiten.GamepadID][2]float64
+	connected []ebiten.GamepadID
+	removed   []ebiten.GamepadID
+}
+
+func (d *fakeDevice) PressedKeys() []ebiten.Key {
+	keys := make([]ebiten.Key, 0, len(d.held))
+	for k := range d.held {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func (d *fakeDevice) KeyPressDuration(key ebiten.Key) int {
+	return d.held[key]
+}
+
+func (d *fakeDevice) InputChars() []rune {
+	return d.chars
+}
+
+func (d *fakeDevice) CursorPosition() (int, int) {
+	return d.cursorX, d.cursorY
+}
+
+func (d *fakeDevice) IsMouseButtonJustPressed(b ebiten.MouseButton) bool {
+	return b == ebiten.MouseButton0 && d.mouseDown
+}
+
+func (d *fakeDevice) IsMouseButtonJustReleased(b ebiten.MouseButton) bool {
+	return b == ebiten.MouseButton0 && d.mouseUp
+}
+
+func (d *fakeDevice) TouchIDs() []ebiten.TouchID {
+	ids := make([]ebiten.TouchID, 0, len(d.touches))
+	for id := range d.touches {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (d *fakeDevice) JustPressedTouchIDs() []ebiten.TouchID {
+	return d.touchDown
+}
+
+func (d *fakeDevice) JustReleasedTouchIDs() []ebiten.TouchID {
+	return d.touchUp
+}
+
+func (d *fakeDevice) TouchPosition(id ebiten.TouchID) (int, int) {
+	p := d.touches[id]
+	return p[0], p[1]
+}
+
+func (d *fakeDevice) GamepadIDs() []ebiten.GamepadID {
+	ids := make([]ebiten.GamepadID, 0, len(d.buttons))
+	for id := range d.buttons {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (d *fakeDevice) JustConnectedGamepadIDs() []ebiten.GamepadID {
+	return d.connected
+}
+
+func (d *fakeDevice) IsGamepadJustDisconnected(id ebiten.GamepadID) bool {
+	for _, v := range d.removed {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *fakeDevice) GamepadAxis(id ebiten.GamepadID, axis ebiten.StandardGamepadAxis) float64 {
+	switch axis {
+	case ebiten.StandardGamepadAxisLeftStickHorizontal:
+		return d.axes[id][0]
+	case ebiten.StandardGamepadAxisLeftStickVertical:
+		return d.axes[id][1]
+	}
+	return 0
+}
+
+func (d *fakeDevice) JustPressedGamepadButtons(id ebiten.GamepadID) []ebiten.StandardGamepadButton {
+	return d.buttons[id]
+}
+
+// startTick ages held keys, so a key pressed before a tick has been held
+// for one tick during it.
+func (d *fakeDevice) startTick() {
+	for k := range d.held {
+		d.held[k]++
+	}
+}
+
+// endTick drops the just-pressed and just-released state, which like
+// inpututil's lasts for a single tick whether it was read or not.
+func (d *fakeDevice) endTick() {
+	d.chars = nil
+	d.mouseDown = false
+	d.mouseUp = false
+	d.touchDown = nil
+	d.touchUp = nil
+	d.connected = nil
+	d.removed = nil
+
+	for id := range d.buttons {
+		d.buttons[id] = nil
+	}
+}
+
+// scriptSource feeds queued events, one batch per tick.
+type scriptSource struct {
+	queue [][]Event
+}
+
+func (s *scriptSource) Poll(events []Event) []Event {
+	if len(s.queue) == 0 {
+		return events
+	}
+
+	events = append(events, s.queue[0]...)
+	s.queue = s.queue[1:]
+
+	return events
+}
+
+// harness drives a Game without a window: input comes from fakeDevice and
+// time from fakeClock, advanced by one frame per tick.
+type harness struct {
+	t      testing.TB
+	g      *Game
+	device *fakeDevice
+	clock  *fakeClock
+}
+
+func newHarness(t testing.TB, mode Mode, word string) *harness {
+	t.Helper()
+
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	device := &fakeDevice{
+		held:    map[ebiten.Key]int{},
+		touches: map[ebiten.TouchID][2]int{},
+		buttons: map[ebiten.GamepadID][]ebiten.StandardGamepadButton{},
+		axes:    map[ebiten.GamepadID][2]float64{},
+	}
+
+	h := &harness{t: t, device: device, clock: clock}
+	h.attach(NewGame(mode))
+	h.g.Word = []rune(word)
+
+	return h
+}
+
+// attach points g at the harness' device and clock.
+func (h *harness) attach(g *Game) {
+	g.SetDevice(h.device)
+	g.Clock = h.clock.Now
+	g.ScaleFactor = func() float64 { return 1 }
+	g.StartedAt = h.clock.Now()
+	h.g = g
+}
+
+// script replaces the game's sources with one that plays batches of events,
+// one batch per tick.
+func (h *harness) script(batches ...[]Event) {
+	h.t.Helper()
+
+	h.g.Sources = []InputSource{&scriptSource{queue: batches}}
+
+	for range batches {
+		h.tick()
+	}
+}
+
+// connectGamepad plugs in gamepad 0.
+func (h *harness) connectGamepad() {
+	h.t.Helper()
+
+	h.device.connected = append(h.device.connected, 0)
+	h.device.buttons[0] = nil
+	h.tick()
+}
+
+func (h *harness) stick(x, y float64) {
+	h.device.axes[0] = [2]float64{x, y}
+}
+
+func (h *harness) button(b ebiten.StandardGamepadButton) {
+	h.t.Helper()
+
+	h.device.buttons[0] = append(h.device.buttons[0], b)
+	h.tick()
+}
+
+func (h *harness) tick() {
+	h.t.Helper()
+
+	h.clock.Advance(tick)
+	h.device.startTick()
+
+	err := h.g.Update()
+	h.device.endTick()
+
+	if err != nil {
+		h.t.Fatal(err)
+	}
+}
+
+func (h *harness) wait(d time.Duration) {
+	h.t.Helper()
+
+	for end := h.clock.Now().Add(d); h.clock.Now().Before(end); {
+		h.tick()
+	}
+}
+
+// key presses and releases k within one tick.
+func (h *harness) key(k ebiten.Key) {
+	h.t.Helper()
+
+	h.hold(k, 1)
+}
+
+// hold keeps k down for n ticks, then releases it.
+func (h *harness) hold(k ebiten.Key, n int) {
+	h.t.Helper()
+
+	h.device.held[k] = 0
+	for range n {
+		h.tick()
+	}
+	delete(h.device.held, k)
+}
+
+func (h *harness) char(c rune) {
+	h.t.Helper()
+
+	h.device.chars = append(h.device.chars, c)
+	h.tick()
+}
+
+// typeRunes types letters as characters and everything else by key, the
+// way the default LAYOUT_CHARS reads them.
+func (h *harness) typeRunes(s string) {
+	h.t.Helper()
+
+	for _, l := range s {
+		if IsAlphabetLetter(l) && h.g.InputLayout != LAYOUT_PHYSICAL {
+			h.char(l)
+			continue
+		}
+		h.key(keyFor(h.t, l))
+	}
+}
+
+// guess types a word and submits it.
+func (h *harness) guess(w string) {
+	h.t.Helper()
+
+	h.typeRunes(w)
+	h.key(ebiten.KeyEnter)
+}
+
+func (h *harness) mouseDown(x, y int) {
+	h.device.cursorX, h.device.cursorY = x, y
+	h.device.mouseDown = true
+	h.tick()
+}
+
+func (h *harness) mouseUp(x, y int) {
+	h.device.cursorX, h.device.cursorY = x, y
+	h.device.mouseUp = true
+	h.tick()
+}
+
+func (h *harness) touchDown(id ebiten.TouchID, x, y int) {
+	h.device.touches[id] = [2]int{x, y}
+	h.device.touchDown = append(h.device.touchDown, id)
+	h.tick()
+}
+
+func (h *harness) touchMove(id ebiten.TouchID, x, y int) {
+	h.device.touches[id] = [2]int{x, y}
+	h.tick()
+}
+
+func (h *harness) touchUp(id ebiten.TouchID) {
+	delete(h.device.touches, id)
+	h.device.touchUp = append(h.device.touchUp, id)
+	h.tick()
+}
+
+func (h *harness) click(id string) {
+	h.t.Helper()
+
+	x, y := h.center(id)
+	h.mouseDown(x, y)
+	h.mouseUp(x, y)
+}
+
+func (h *harness) tap(id string) {
+	h.t.Helper()
+
+	x, y := h.center(id)
+	h.touchDown(1, x, y)
+	h.touchUp(1)
+}
+
+func (h *harness) center(id string) (int, int) {
+	h.t.Helper()
+
+	root := h.g.Node
+	if h.g.Stage == SETTINGS {
+		root = h.g.SettingsNode
+	}
+
+	node := FindNode(root, id)
+	if node == nil {
+		h.t.Fatalf("no layout node %q", id)
+	}
+
+	return int(node.X + node.W/2), int(node.Y + node.H/2)
+}
+
+// waitForAnalysis ticks until the background solver has finished.
+func (h *harness) waitForAnalysis() {
+	h.t.Helper()
+
+	for range 600 {
+		if h.g.Analysis != nil {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+		h.tick()
+	}
+
+	h.t.Fatal("analysis did not finish")
+}
+
+func (h *harness) row(r int) string {
+	if r >= len(h.g.GuessedWords) {
+		return ""
+	}
+	return string(h.g.GuessedWords[r])
+}
+
+func keyFor(t testing.TB, l rune) ebiten.Key {
+	t.Helper()
+
+	for k := ebiten.Key(0); k <= ebiten.KeyMax; k++ {
+		if MapInputToRune(k) == l {
+			return k
+		}
+	}
+
+	t.Fatalf("no key types %q", l)
+	return 0
+}
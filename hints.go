@@ -0,0 +1,174 @@
+package main
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"slices"
+)
+
+const (
+	MaxHints       = 3
+	EliminateCount = 3
+	alphabet       = "абвгдежзийклмнопрстуфхцчшщъыьэюя"
+)
+
+type HintKind byte
+
+const (
+	HINT_REVEAL HintKind = iota
+	HINT_ELIMINATE
+)
+
+type Hint struct {
+	Kind     HintKind
+	Position int
+	Letters  []rune
+	// Turn is how many guesses had been submitted when the hint was taken.
+	Turn int
+}
+
+// HintsLeft is always zero in ABSURDLE mode, where there is no word to
+// give away yet.
+func (g *Game) HintsLeft() int {
+	if g.Mode == ABSURDLE {
+		return 0
+	}
+	return MaxHints - len(g.Hints)
+}
+
+func (g *Game) HandleHint(kind HintKind) error {
+	if g.HintsLeft() <= 0 {
+		return nil
+	}
+
+	var h *Hint
+
+	switch kind {
+	case HINT_REVEAL:
+		h = g.revealHint()
+	case HINT_ELIMINATE:
+		h = g.eliminateHint()
+	}
+
+	if h == nil {
+		g.StartShaking()
+		return nil
+	}
+
+	h.Turn = g.LastSubmitted + 1
+	g.Hints = append(g.Hints, *h)
+
+	return nil
+}
+
+// revealHint opens the leftmost position the player has neither guessed nor
+// been shown yet.
+func (g *Game) revealHint() *Hint {
+	for i := range g.Word {
+		if g.IsPositionKnown(i) {
+			continue
+		}
+
+		return &Hint{
+			Kind:     HINT_REVEAL,
+			Position: i,
+			Letters:  []rune{g.Word[i]},
+		}
+	}
+
+	return nil
+}
+
+// eliminateHint greys out a few letters that are absent from the word and
+// were not tried yet. The pick is seeded by the word and hint count so a
+// replayed round gets the same letters.
+func (g *Game) eliminateHint() *Hint {
+	pool := make([]rune, 0, len(alphabet))
+
+	for _, l := range alphabet {
+		if slices.Contains(g.Word, l) || g.IsLetterGuessed(l) || g.IsLetterEliminated(l) {
+			continue
+		}
+		pool = append(pool, l)
+	}
+
+	if len(pool) == 0 {
+		return nil
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(string(g.Word)))
+	r := rand.New(rand.NewSource(int64(h.Sum64()) + int64(len(g.Hints))))
+	r.Shuffle(len(pool), func(i, j int) {
+		pool[i], pool[j] = pool[j], pool[i]
+	})
+
+	return &Hint{
+		Kind:     HINT_ELIMINATE,
+		Position: -1,
+		Letters:  pool[:min(EliminateCount, len(pool))],
+	}
+}
+
+// Allows reports whether w could still be the hidden word given the hint.
+func (h Hint) Allows(w solverWord) bool {
+	if h.Kind == HINT_REVEAL {
+		return w.runes[h.Position] == h.Letters[0]
+	}
+
+	for _, l := range h.Letters {
+		if w.Contains(l) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *Game) IsPositionKnown(i int) bool {
+	if _, ok := g.RevealedLetter(i); ok {
+		return true
+	}
+
+	for r, w := range g.GuessedWords {
+		if r > g.LastSubmitted {
+			break
+		}
+		if i < len(w) && w[i] == g.Word[i] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (g *Game) RevealedLetter(i int) (rune, bool) {
+	for _, h := range g.Hints {
+		if h.Kind == HINT_REVEAL && h.Position == i {
+			return h.Letters[0], true
+		}
+	}
+	return ' ', false
+}
+
+func (g *Game) IsLetterRevealed(letter rune) bool {
+	for _, h := range g.Hints {
+		if h.Kind == HINT_REVEAL && h.Letters[0] == letter {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Game) IsLetterEliminated(letter rune) bool {
+	for _, h := range g.Hints {
+		if h.Kind != HINT_ELIMINATE {
+			continue
+		}
+		for _, l := range h.Letters {
+			if l == letter {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -2,17 +2,12 @@ package main
 
 import (
 	"strings"
-	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	la "github.com/laranatech/gorana/layout"
 )
 
-const (
-	clickInputDebounce = 250
-)
-
 var keymap map[string]rune = map[string]rune{
 	"q":         'й',
 	"w":         'ц',
@@ -24,6 +19,7 @@ var keymap map[string]rune = map[string]rune{
 	"i":         'ш',
 	"o":         'щ',
 	"p":         'з',
+	"`":         'е',
 	"[":         'х',
 	"]":         'ъ',
 	"a":         'ф',
@@ -48,16 +44,96 @@ var keymap map[string]rune = map[string]rune{
 	".":         'ю',
 	"Enter":     '+',
 	"Backspace": '-',
+	"F1":        '?',
+	"F2":        '!',
+}
+
+// Device is the raw input the game polls every tick. EbitenDevice reads the
+// real keyboard, mouse and touchscreen; tests substitute a scripted one.
+type Device interface {
+	PressedKeys() []ebiten.Key
+	KeyPressDuration(key ebiten.Key) int
+	InputChars() []rune
+	CursorPosition() (int, int)
+	IsMouseButtonJustPressed(b ebiten.MouseButton) bool
+	IsMouseButtonJustReleased(b ebiten.MouseButton) bool
+	TouchIDs() []ebiten.TouchID
+	JustPressedTouchIDs() []ebiten.TouchID
+	JustReleasedTouchIDs() []ebiten.TouchID
+	TouchPosition(id ebiten.TouchID) (int, int)
+	GamepadIDs() []ebiten.GamepadID
+	JustConnectedGamepadIDs() []ebiten.GamepadID
+	IsGamepadJustDisconnected(id ebiten.GamepadID) bool
+	JustPressedGamepadButtons(id ebiten.GamepadID) []ebiten.StandardGamepadButton
+	GamepadAxis(id ebiten.GamepadID, axis ebiten.StandardGamepadAxis) float64
+}
+
+type EbitenDevice struct{}
+
+func (EbitenDevice) PressedKeys() []ebiten.Key {
+	return inpututil.AppendPressedKeys(nil)
+}
+
+func (EbitenDevice) KeyPressDuration(key ebiten.Key) int {
+	return inpututil.KeyPressDuration(key)
+}
+
+func (EbitenDevice) InputChars() []rune {
+	return ebiten.AppendInputChars(nil)
+}
+
+func (EbitenDevice) CursorPosition() (int, int) {
+	return ebiten.CursorPosition()
+}
+
+func (EbitenDevice) IsMouseButtonJustPressed(b ebiten.MouseButton) bool {
+	return inpututil.IsMouseButtonJustPressed(b)
+}
+
+func (EbitenDevice) IsMouseButtonJustReleased(b ebiten.MouseButton) bool {
+	return inpututil.IsMouseButtonJustReleased(b)
+}
+
+func (EbitenDevice) TouchIDs() []ebiten.TouchID {
+	return ebiten.AppendTouchIDs(nil)
+}
+
+func (EbitenDevice) JustPressedTouchIDs() []ebiten.TouchID {
+	return inpututil.AppendJustPressedTouchIDs(nil)
+}
+
+func (EbitenDevice) JustReleasedTouchIDs() []ebiten.TouchID {
+	return inpututil.AppendJustReleasedTouchIDs(nil)
+}
+
+func (EbitenDevice) TouchPosition(id ebiten.TouchID) (int, int) {
+	return ebiten.TouchPosition(id)
 }
 
-func (g *Game) InputKey() rune {
-	keys := inpututil.AppendJustReleasedKeys(nil)
+func (EbitenDevice) GamepadIDs() []ebiten.GamepadID {
+	return ebiten.AppendGamepadIDs(nil)
+}
+
+func (EbitenDevice) JustConnectedGamepadIDs() []ebiten.GamepadID {
+	return inpututil.AppendJustConnectedGamepadIDs(nil)
+}
 
-	for _, key := range keys {
-		return MapInputToRune(key)
+func (EbitenDevice) IsGamepadJustDisconnected(id ebiten.GamepadID) bool {
+	return inpututil.IsGamepadJustDisconnected(id)
+}
+
+func (EbitenDevice) GamepadAxis(id ebiten.GamepadID, axis ebiten.StandardGamepadAxis) float64 {
+	if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+		return 0
 	}
+	return ebiten.StandardGamepadAxisValue(id, axis)
+}
 
-	return ' '
+func (EbitenDevice) JustPressedGamepadButtons(id ebiten.GamepadID) []ebiten.StandardGamepadButton {
+	if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+		return nil
+	}
+	return inpututil.AppendJustPressedStandardGamepadButtons(id, nil)
 }
 
 func MapInputToRune(k ebiten.Key) rune {
@@ -68,6 +144,10 @@ func MapInputToRune(k ebiten.Key) rune {
 		name = "Enter"
 	case ebiten.KeyBackspace:
 		name = "Backspace"
+	case ebiten.KeyF1:
+		name = "F1"
+	case ebiten.KeyF2:
+		name = "F2"
 	case ebiten.KeyQ:
 		name = "q"
 	case ebiten.KeyW:
@@ -88,6 +168,8 @@ func MapInputToRune(k ebiten.Key) rune {
 		name = "o"
 	case ebiten.KeyP:
 		name = "p"
+	case ebiten.KeyBackquote:
+		name = "`"
 	case ebiten.KeyBracketLeft:
 		name = "["
 	case ebiten.KeyBracketRight:
@@ -142,12 +224,9 @@ func MapInputToRune(k ebiten.Key) rune {
 	return r
 }
 
-func (g *Game) IsOkToClick() bool {
-	return time.Since(g.LastClickedAt) > clickInputDebounce*time.Millisecond
-}
-
+// FindHovered returns the key or attempt tile under x, y.
 func FindHovered(node *la.OutputItem, x, y float32) *la.OutputItem {
-	if strings.HasPrefix(node.Id, "key_") {
+	if strings.HasPrefix(node.Id, "key_") || strings.HasPrefix(node.Id, "attempt_") {
 		if Collide(node, x, y) {
 			return node
 		}
@@ -163,29 +242,16 @@ func FindHovered(node *la.OutputItem, x, y float32) *la.OutputItem {
 	return nil
 }
 
-func (g *Game) IsPressed() bool {
-	if ebiten.IsMouseButtonPressed(ebiten.MouseButton0) {
-		return true
-	}
-
-	touches := ebiten.AppendTouchIDs(nil)
-
-	return len(touches) > 0
-}
-
-func (g *Game) CursorPosition() (float32, float32) {
-	x, y := ebiten.CursorPosition()
-	if x != 0 && y != 0 {
-		return float32(x), float32(y)
+func FindNode(node *la.OutputItem, id string) *la.OutputItem {
+	if node.Id == id {
+		return node
 	}
 
-	touches := ebiten.AppendTouchIDs(nil)
-
-	if len(touches) == 0 {
-		return 0, 0
+	for _, child := range node.Children {
+		if found := FindNode(child, id); found != nil {
+			return found
+		}
 	}
 
-	x, y = ebiten.TouchPosition(touches[0])
-
-	return float32(x), float32(y)
+	return nil
 }
@@ -2,22 +2,24 @@ package main
 
 import (
 	_ "embed"
+	"flag"
+	"io"
 	"log"
+	"os"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/e-kucheriavyi/five-letters/pallete"
 	"github.com/hajimehoshi/ebiten/v2"
 	la "github.com/laranatech/gorana/layout"
 )
 
 const (
-	screenW         = 720
-	screenH         = 720
-	keyGap          = 8
-	keyRowGap       = 8
-	keySide         = (screenW - (keyGap * 11)) / 12
-	attemptItemSide = 58
+	screenW   = 720
+	screenH   = 720
+	keyGap    = 8
+	keyRowGap = 8
 )
 
 type Stage byte
@@ -26,6 +28,14 @@ const (
 	INTRO Stage = iota
 	GAME
 	SCORE
+	SETTINGS
+)
+
+type Mode byte
+
+const (
+	DAILY Mode = iota
+	ABSURDLE
 )
 
 type LetterStatus byte
@@ -39,29 +49,91 @@ const (
 
 type Game struct {
 	Stage            Stage
+	Mode             Mode
 	Word             []rune
 	GuessedWords     [][]rune
+	Feedback         []Pattern
+	Candidates       []solverWord
+	Settings         Settings
+	SettingsPath     string
+	Theme            *pallete.Theme
+	Announcer        Announcer
+	Speaker          Speaker
+	Vibrator         Vibrator
+	SoundQueue       []QueuedSound
+	SettingsNode     *la.OutputItem
+	SettingsFocus    int
+	ReturnStage      Stage
+	Width            int
+	Height           int
+	Scale            float64
+	ScaleFactor      func() float64
+	Metrics          Metrics
 	Node             *la.OutputItem
 	Hovered          *la.OutputItem
-	LastClickedAt    time.Time
+	Focused          *la.OutputItem
+	Device           Device
+	InputLayout      InputLayout
+	KeyRepeat        KeyRepeat
+	Sources          []InputSource
+	Clock            func() time.Time
+	Pointers         map[int]*PointerState
 	LastKeyPressedAt time.Time
 	LastSubmitted    int
+	Cursor           int
+	Composed         []rune
 	ShakeTimer       int
+	StartedAt        time.Time
+	Recording        *Replay
+	RecordOut        io.Writer
+	Playback         *Replay
+	PlaybackIndex    int
+	Hints            []Hint
+	Stats            Stats
+	StatsPath        string
+	ShareOut         io.Writer
+	Analysis         []GuessAnalysis
+	analysisDone     chan []GuessAnalysis
 }
 
-func NewGame() *Game {
-	return &Game{
+func NewGame(mode Mode) *Game {
+	g := &Game{
 		Stage:         GAME,
+		Mode:          mode,
 		Word:          []rune(GetWord(time.Now())),
 		GuessedWords:  make([][]rune, 0, 6),
-		Node:          CreateLayout(),
+		Feedback:      make([]Pattern, 0, 6),
+		Pointers:      map[int]*PointerState{},
+		KeyRepeat:     DefaultKeyRepeat,
+		Clock:         time.Now,
+		Scale:         1,
+		ScaleFactor:   DeviceScaleFactor,
+		Vibrator:      DefaultVibrator(),
 		LastSubmitted: -1,
+		StartedAt:     time.Now(),
+	}
+
+	g.Resize(screenW, screenH)
+	g.SetDevice(EbitenDevice{})
+	g.ApplySettings(DefaultSettings)
+
+	if mode == ABSURDLE {
+		g.Candidates = SolverWords()
 	}
+
+	return g
 }
 
 func (g *Game) Update() error {
-	if g.Stage == GAME {
+	g.UpdateSounds()
+
+	switch g.Stage {
+	case GAME:
 		g.UpdateGame()
+	case SCORE:
+		g.UpdateScore()
+	case SETTINGS:
+		g.UpdateSettings()
 	}
 
 	return nil
@@ -79,69 +151,315 @@ func Collide(node *la.OutputItem, x, y float32) bool {
 	return true
 }
 
+// SetDevice switches the raw input and rebuilds the default sources on top
+// of it.
+func (g *Game) SetDevice(d Device) {
+	g.Device = d
+	g.Sources = DefaultSources(d, g.InputLayout, g.KeyRepeat)
+}
+
+func (g *Game) SetInputLayout(layout InputLayout) {
+	g.InputLayout = layout
+	g.SetDevice(g.Device)
+}
+
+func (g *Game) SetKeyRepeat(repeat KeyRepeat) {
+	g.KeyRepeat = repeat
+	g.SetDevice(g.Device)
+}
+
+func (g *Game) PollEvents() []Event {
+	events := make([]Event, 0, 4)
+
+	for _, s := range g.Sources {
+		events = s.Poll(events)
+	}
+
+	return events
+}
+
 func (g *Game) UpdateGame() error {
-	l := g.InputKey()
+	if g.Playback != nil {
+		return g.UpdatePlayback()
+	}
+
+	for _, e := range g.PollEvents() {
+		if err := g.HandleEvent(e); err != nil {
+			return err
+		}
 
-	if l != ' ' {
-		g.LastKeyPressedAt = time.Now()
-		return g.HandleInput(l)
+		if g.Stage != GAME {
+			break
+		}
 	}
 
-	x, y := g.CursorPosition()
-	g.Hovered = FindHovered(g.Node, float32(x), float32(y))
+	return nil
+}
 
-	isPressed := g.IsPressed()
+func (g *Game) HandleEvent(e Event) error {
+	switch e.Kind {
+	case EVENT_LETTER, EVENT_BACKSPACE, EVENT_CLEAR, EVENT_SUBMIT, EVENT_HINT, EVENT_ACTIVATE, EVENT_POINTER_UP:
+		g.Composed = g.Composed[:0]
+	}
 
-	if isPressed {
-		if !g.IsOkToClick() {
-			return nil
+	switch e.Kind {
+	case EVENT_COMPOSE:
+		g.Focused = nil
+		g.LastKeyPressedAt = g.Clock()
+		return g.HandleCompose(e)
+	case EVENT_LETTER:
+		g.Focused = nil
+		g.LastKeyPressedAt = g.Clock()
+		return g.HandleInput(e.Input())
+	case EVENT_BACKSPACE, EVENT_CLEAR, EVENT_SUBMIT, EVENT_HINT:
+		g.LastKeyPressedAt = g.Clock()
+		return g.HandleInput(e.Input())
+	case EVENT_NAVIGATE:
+		g.MoveFocus(e.Direction)
+	case EVENT_GAMEPAD_CONNECTED:
+		if g.Focused == nil {
+			g.MoveFocus(NAV_RIGHT)
 		}
+	case EVENT_THEME:
+		g.CycleTheme()
+	case EVENT_COLOR_BLIND:
+		g.ToggleColorBlind()
+	case EVENT_DESCRIBE:
+		g.Announce(g.DescribeBoard())
+	case EVENT_SETTINGS:
+		g.OpenSettings()
+	case EVENT_ACTIVATE:
+		g.LastKeyPressedAt = g.Clock()
+		if g.Focused != nil {
+			return g.PressKey(KeyRune(g.Focused))
+		}
+		return g.HandleInput('+')
+	case EVENT_POINTER_MOVE:
+		g.HandlePointerMove(e)
+	case EVENT_POINTER_DOWN:
+		g.HandlePointerDown(e)
+	case EVENT_POINTER_UP:
+		return g.HandlePointerUp(e)
+	}
 
-		g.LastClickedAt = time.Now()
+	return nil
+}
 
-		if g.Hovered == nil {
-			return nil
+// HandleCompose replaces the letters of the current composition with
+// e.Text. Only letters that actually made it into the row are taken back,
+// so a composition started on a full row never deletes a real letter.
+func (g *Game) HandleCompose(e Event) error {
+	for range min(e.Replace, len(g.Composed)) {
+		if err := g.takeBackComposed(); err != nil {
+			return err
+		}
+	}
+	g.Composed = g.Composed[:0]
+
+	for _, l := range e.Text {
+		cursor := g.Cursor
+		w := g.GuessedWords
+		overwritten := rune(0)
+		if len(w) > 0 && cursor < len(w[len(w)-1]) {
+			overwritten = w[len(w)-1][cursor]
 		}
 
-		tmp := strings.ReplaceAll(g.Hovered.Id, "key_", "")
+		if err := g.HandleInput(l); err != nil {
+			return err
+		}
+		if g.Cursor != cursor {
+			g.Composed = append(g.Composed, overwritten)
+		}
+	}
 
-		l = []rune(tmp)[0]
+	return nil
+}
+
+// takeBackComposed undoes the last composed letter through inputs a replay
+// can repeat: an appended letter is deleted at the end of the row, an
+// overwritten one gets its old letter typed back in place.
+func (g *Game) takeBackComposed() error {
+	last := len(g.Composed) - 1
+	overwritten := g.Composed[last]
+	g.Composed = g.Composed[:last]
 
-		return g.HandleInput(l)
+	if overwritten == 0 {
+		return g.HandleInput('-')
+	}
+
+	at := '0' + rune(g.Cursor-1)
+	for _, l := range []rune{at, overwritten, at} {
+		if err := g.HandleInput(l); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// PointerState follows one mouse button or finger from press to release.
+// Node is the key or tile it went down on.
+type PointerState struct {
+	Node *la.OutputItem
+	X    float32
+	Y    float32
+}
+
+// IsOverNode reports whether the pointer is still inside the node it went
+// down on.
+func (p *PointerState) IsOverNode() bool {
+	return Collide(p.Node, p.X, p.Y)
+}
+
+// HandlePointerMove tracks pressed pointers. Only the mouse hovers: a
+// finger has nothing to hover with once it is lifted.
+func (g *Game) HandlePointerMove(e Event) {
+	g.Focused = nil
+
+	if p, ok := g.Pointers[e.Pointer]; ok {
+		p.X, p.Y = e.X, e.Y
+	}
+
+	if e.Pointer == MousePointer {
+		g.Hovered = FindHovered(g.Node, e.X, e.Y)
+	}
+}
+
+// HandlePointerDown remembers which key a pointer went down on. The key is
+// only pressed when the same pointer is released over it, so every finger
+// is tracked on its own and sliding off a key cancels it.
+func (g *Game) HandlePointerDown(e Event) {
+	g.Focused = nil
+
+	key := FindHovered(g.Node, e.X, e.Y)
+
+	if e.Pointer == MousePointer {
+		g.Hovered = key
+	}
+
+	if key == nil {
+		delete(g.Pointers, e.Pointer)
+		return
+	}
+
+	g.Pointers[e.Pointer] = &PointerState{Node: key, X: e.X, Y: e.Y}
+}
+
+func (g *Game) HandlePointerUp(e Event) error {
+	p, ok := g.Pointers[e.Pointer]
+	delete(g.Pointers, e.Pointer)
+
+	if !ok {
+		return nil
+	}
+
+	p.X, p.Y = e.X, e.Y
+
+	if !p.IsOverNode() {
+		return nil
+	}
+
+	if strings.HasPrefix(p.Node.Id, "attempt_") {
+		r, i := ExtractIndecies(p.Node.Id)
+		if r != g.CurrentRow() {
+			return nil
+		}
+		return g.HandleInput(rune('0' + i))
+	}
+
+	return g.PressKey(KeyRune(p.Node))
+}
+
+// PressKey acts on an on-screen key: the settings key opens the settings
+// screen, every other key is game input.
+func (g *Game) PressKey(l rune) error {
+	g.Vibrate(HAPTIC_KEY)
+
+	if l == SETTINGS_KEY {
+		g.OpenSettings()
+		return nil
+	}
+	return g.HandleInput(l)
+}
+
 func (g *Game) HandleInput(l rune) error {
+	g.RecordInput(l)
+	g.PlaySound(SOUND_CLICK)
+
 	if l == '-' {
 		return g.HandleBackspace()
 	}
 
+	if l == '_' {
+		return g.HandleClearRow()
+	}
+
+	if l >= '0' && l < '0'+wordLength {
+		return g.MoveCursor(int(l - '0'))
+	}
+
 	if l == '+' {
 		return g.HandleSubmit()
 	}
 
+	if l == '?' {
+		return g.HandleHint(HINT_REVEAL)
+	}
+
+	if l == '!' {
+		return g.HandleHint(HINT_ELIMINATE)
+	}
+
 	return g.HandleLetterClick(l)
 }
 
+// HandleLetterClick puts l at the cursor: it overwrites the letter there
+// or, at the end of the row, appends it.
 func (g *Game) HandleLetterClick(l rune) error {
 	if len(g.GuessedWords) == 0 {
-		g.GuessedWords = append(g.GuessedWords, []rune{l})
-		return nil
+		g.GuessedWords = append(g.GuessedWords, make([]rune, 0, 5))
+		g.Cursor = 0
 	}
 
 	lastIndex := len(g.GuessedWords) - 1
 
 	w := g.GuessedWords[lastIndex]
 
+	if g.Cursor < len(w) {
+		w[g.Cursor] = l
+		g.Cursor++
+		return nil
+	}
+
 	if len(w) < 5 {
 		g.GuessedWords[lastIndex] = append(g.GuessedWords[lastIndex], l)
+		g.Cursor = len(w) + 1
 	}
 
 	return nil
 }
 
+// MoveCursor places the cursor on tile i of the current row. It never goes
+// past the typed letters, so a row cannot have gaps.
+func (g *Game) MoveCursor(i int) error {
+	if len(g.GuessedWords) == 0 || g.CurrentRow() == g.LastSubmitted {
+		return nil
+	}
+
+	g.Cursor = min(i, len(g.GuessedWords[g.CurrentRow()]))
+
+	return nil
+}
+
+// CurrentRow is the index of the row being typed into.
+func (g *Game) CurrentRow() int {
+	if len(g.GuessedWords) == 0 {
+		return 0
+	}
+	return len(g.GuessedWords) - 1
+}
+
+// HandleBackspace deletes the letter before the cursor and closes the gap.
 func (g *Game) HandleBackspace() error {
 	if len(g.GuessedWords) == 0 {
 		return nil
@@ -153,10 +471,25 @@ func (g *Game) HandleBackspace() error {
 		return nil
 	}
 
-	if len(g.GuessedWords[lastIndex]) > 0 {
-		g.GuessedWords[lastIndex] = g.GuessedWords[lastIndex][:len(g.GuessedWords[lastIndex])-1]
+	if g.Cursor == 0 {
+		return nil
 	}
 
+	w := g.GuessedWords[lastIndex]
+	g.GuessedWords[lastIndex] = append(w[:g.Cursor-1], w[g.Cursor:]...)
+	g.Cursor--
+
+	return nil
+}
+
+func (g *Game) HandleClearRow() error {
+	if len(g.GuessedWords) == 0 || g.CurrentRow() == g.LastSubmitted {
+		return nil
+	}
+
+	g.GuessedWords[g.CurrentRow()] = g.GuessedWords[g.CurrentRow()][:0]
+	g.Cursor = 0
+
 	return nil
 }
 
@@ -169,40 +502,105 @@ func (g *Game) HandleSubmit() error {
 
 	if len(g.GuessedWords[lastIndex]) != 5 {
 		g.StartShaking()
+		g.Announce("Нужно пять букв")
 		return nil
 	}
 
 	if !ValidateWord(string(g.GuessedWords[lastIndex])) {
 		g.StartShaking()
+		g.Announce("Такого слова нет в словаре")
 		return nil
 	}
 
+	if msg := g.HardModeViolation(g.GuessedWords[lastIndex]); msg != "" {
+		g.StartShaking()
+		g.Announce(msg)
+		return nil
+	}
+
+	g.Feedback = append(g.Feedback, g.Evaluate(g.GuessedWords[lastIndex]))
+	g.LastSubmitted = lastIndex
+
 	if g.IsWordGuessed() || len(g.GuessedWords) == 6 {
 		g.Stage = SCORE
+		if g.IsWordGuessed() {
+			g.Vibrate(HAPTIC_WIN)
+		}
+		g.AnnounceSubmit()
+		g.QueueRevealSounds(lastIndex)
+		g.RecordStats()
+		g.StartAnalysis()
 		return nil
 	}
 
-	g.LastSubmitted = lastIndex
+	g.AnnounceSubmit()
+	g.QueueRevealSounds(lastIndex)
 	g.GuessedWords = append(g.GuessedWords, make([]rune, 0, 5))
+	g.Cursor = 0
 
 	return nil
 }
 
-func (g *Game) IsWordGuessed() bool {
-	lastIndex := len(g.GuessedWords) - 1
-
-	w := g.GuessedWords[lastIndex]
+// StartAnalysis runs the solver over the finished round in the background,
+// since searching the whole dictionary takes longer than a frame.
+func (g *Game) StartAnalysis() {
+	done := make(chan []GuessAnalysis, 1)
+	g.analysisDone = done
+
+	guesses := make([][]rune, len(g.GuessedWords))
+	copy(guesses, g.GuessedWords)
+	word := g.Word
+	hints := make([]Hint, len(g.Hints))
+	copy(hints, g.Hints)
+
+	go func() {
+		done <- Analyze(guesses, word, hints)
+	}()
+}
 
-	for i := range 6 {
-		if i > 4 {
-			return true
+func (g *Game) UpdateScore() error {
+	if g.analysisDone != nil {
+		select {
+		case g.Analysis = <-g.analysisDone:
+			g.analysisDone = nil
+		default:
 		}
-		if w[i] != g.Word[i] {
-			return false
+	}
+
+	for _, e := range g.PollEvents() {
+		g.HandleScoreEvent(e)
+
+		if g.Stage != SCORE {
+			break
 		}
 	}
 
-	return false
+	return nil
+}
+
+// HandleScoreEvent keeps the keys that do not touch the board working on
+// the score screen and shares the result.
+func (g *Game) HandleScoreEvent(e Event) {
+	switch e.Kind {
+	case EVENT_DESCRIBE:
+		g.Announce(g.DescribeBoard())
+	case EVENT_THEME:
+		g.CycleTheme()
+	case EVENT_COLOR_BLIND:
+		g.ToggleColorBlind()
+	case EVENT_SETTINGS:
+		g.OpenSettings()
+	case EVENT_SHARE:
+		g.Share()
+	}
+}
+
+func (g *Game) IsWordGuessed() bool {
+	if len(g.Feedback) == 0 {
+		return false
+	}
+
+	return g.Feedback[len(g.Feedback)-1] == solvedPattern
 }
 
 func (g *Game) IsLetterGuessed(letter rune) bool {
@@ -223,30 +621,24 @@ func (g *Game) IsLetterGuessed(letter rune) bool {
 	return false
 }
 
+// IsLetterInWord reports whether feedback so far has shown the letter to be
+// part of the word.
 func (g *Game) IsLetterInWord(letter rune) bool {
-	if g.LastSubmitted == -1 {
-		return false
-	}
-
-	for _, c := range g.Word {
-		if c == letter {
-			return true
+	for r, p := range g.Feedback {
+		for i, c := range g.GuessedWords[r] {
+			if c == letter && p.At(i) != WRONG {
+				return true
+			}
 		}
 	}
 	return false
 }
 
 func (g *Game) GetLetterStatus(r, i int, letter rune) LetterStatus {
-	if g.LastSubmitted < r {
+	if g.LastSubmitted < r || r >= len(g.Feedback) {
 		return PENDING
 	}
-	if g.Word[i] == letter {
-		return GUESSED
-	}
-	if g.IsLetterInWord(letter) {
-		return PRESENT
-	}
-	return WRONG
+	return g.Feedback[r].At(i)
 }
 
 func ExtractIndecies(str string) (int, int) {
@@ -261,10 +653,96 @@ func ExtractIndecies(str string) (int, int) {
 }
 
 func main() {
-	game := NewGame()
+	absurdle := flag.Bool("absurdle", false, "play against an adversary with no fixed word")
+	record := flag.String("record", "", "save the round's inputs to this file")
+	replay := flag.String("replay", "", "play back a round saved with -record")
+	layout := flag.String("layout", "", "letter input: chars, physical or translit; overrides the setting")
+	repeatDelay := flag.Int("repeat-delay", 500, "milliseconds before a held key repeats")
+	repeatRate := flag.Int("repeat-rate", 15, "repeats per second of a held key, 0 to turn off")
+	font := flag.String("font", "pixel", "embedded font name or path to a BDF file")
+	smooth := flag.Bool("smooth", false, "draw text with a TrueType font instead of the pixel font; overrides the setting")
+	announce := flag.String("announce", "", "read feedback out: stdout or speech (spd-say)")
+	flag.Parse()
+
+	if *font != "pixel" {
+		f, err := LoadFont(*font)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		SetTextFont(f)
+	}
+
+	mode := DAILY
+	if *absurdle {
+		mode = ABSURDLE
+	}
+
+	game := NewGame(mode)
+
+	if *replay != "" {
+		r, err := LoadReplay(*replay)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		// Playback keeps the defaults: the player's own settings, hard
+		// mode above all, would change how the recorded inputs play out.
+		game = NewPlayback(r)
+	} else {
+		game.SettingsPath = SettingsPath()
+		if game.SettingsPath != "" {
+			settings, err := LoadSettings(game.SettingsPath)
+			if err != nil {
+				log.Println(err.Error())
+			}
+			game.ApplySettings(settings)
+		}
+
+		game.StatsPath = StatsPath()
+		if game.StatsPath != "" {
+			game.LoadStats()
+		}
+
+		if *record != "" {
+			f, err := os.Create(*record)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			defer f.Close()
+
+			if err := game.StartRecording(f); err != nil {
+				log.Fatal(err.Error())
+			}
+		}
+	}
+
+	switch *announce {
+	case "":
+	case "stdout":
+		game.Announcer = WriterAnnouncer{W: os.Stdout}
+	case "speech":
+		game.Announcer = SpeechAnnouncer{}
+	default:
+		log.Fatalf("unknown -announce %q, want stdout or speech", *announce)
+	}
+
+	game.Speaker = NewAudioSpeaker()
+	game.ShareOut = os.Stdout
+
+	if l, ok := ParseInputLayout(*layout); ok {
+		game.SetInputLayout(l)
+	}
+
+	if *smooth {
+		if err := SetTextRenderer(TEXT_SMOOTH); err != nil {
+			log.Fatal(err.Error())
+		}
+	}
+
+	game.SetKeyRepeat(KeyRepeatFromTime(*repeatDelay, *repeatRate))
 
 	ebiten.SetWindowSize(screenW, screenH)
 	ebiten.SetWindowTitle("Five letters")
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 
 	if err := ebiten.RunGame(game); err != nil {
 		log.Fatal(err.Error())
@@ -4,10 +4,108 @@ import (
 	"image/color"
 )
 
-var BG = color.RGBA{18, 18, 18, 255}
-var FG = color.RGBA{255, 255, 255, 255}
+// Theme assigns a colour to every role the game draws with.
+type Theme struct {
+	Name       string
+	Background color.RGBA
+	Foreground color.RGBA
+	Match      color.RGBA
+	Present    color.RGBA
+	Miss       color.RGBA
+	Passive    color.RGBA
+	Border     color.RGBA
+	Focus      color.RGBA
+}
 
-var MATCH = color.RGBA{133, 192, 249, 255}
-var MISS = color.RGBA{60, 60, 60, 255}
-var PRESENT = color.RGBA{245, 121, 58, 255}
-var PASSIVE = color.RGBA{136, 136, 136, 255}
+var Dark = Theme{
+	Name:       "dark",
+	Background: color.RGBA{18, 18, 18, 255},
+	Foreground: color.RGBA{255, 255, 255, 255},
+	Match:      color.RGBA{133, 192, 249, 255},
+	Present:    color.RGBA{245, 121, 58, 255},
+	Miss:       color.RGBA{60, 60, 60, 255},
+	Passive:    color.RGBA{136, 136, 136, 255},
+	Border:     color.RGBA{255, 255, 255, 255},
+	Focus:      color.RGBA{255, 255, 255, 255},
+}
+
+var Light = Theme{
+	Name:       "light",
+	Background: color.RGBA{250, 250, 250, 255},
+	Foreground: color.RGBA{26, 26, 27, 255},
+	Match:      color.RGBA{110, 175, 240, 255},
+	Present:    color.RGBA{247, 150, 95, 255},
+	Miss:       color.RGBA{205, 205, 210, 255},
+	Passive:    color.RGBA{150, 150, 155, 255},
+	Border:     color.RGBA{120, 124, 126, 255},
+	Focus:      color.RGBA{26, 26, 27, 255},
+}
+
+var HighContrast = Theme{
+	Name:       "contrast",
+	Background: color.RGBA{0, 0, 0, 255},
+	Foreground: color.RGBA{255, 255, 255, 255},
+	Match:      color.RGBA{0, 114, 255, 255},
+	Present:    color.RGBA{230, 100, 0, 255},
+	Miss:       color.RGBA{70, 70, 70, 255},
+	Passive:    color.RGBA{190, 190, 190, 255},
+	Border:     color.RGBA{255, 255, 255, 255},
+	Focus:      color.RGBA{255, 230, 0, 255},
+}
+
+// Themes lists the built-in themes in the order they are cycled through.
+var Themes = []*Theme{&Dark, &Light, &HighContrast}
+
+func ByName(name string) (*Theme, bool) {
+	for _, t := range Themes {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// The colour-blind variants take match and present from the Okabe–Ito
+// palette and move Miss so the three statuses differ in lightness as well
+// as hue, which keeps them apart for protan, deutan and tritan vision and
+// even in greyscale.
+var (
+	DarkColorBlind = withStatus(Dark,
+		color.RGBA{0, 114, 178, 255},
+		color.RGBA{230, 159, 0, 255},
+		color.RGBA{45, 45, 45, 255},
+	)
+	LightColorBlind = withStatus(Light,
+		color.RGBA{0, 90, 160, 255},
+		color.RGBA{240, 228, 66, 255},
+		color.RGBA{160, 160, 165, 255},
+	)
+	HighContrastColorBlind = withStatus(HighContrast,
+		color.RGBA{0, 114, 178, 255},
+		color.RGBA{240, 228, 66, 255},
+		color.RGBA{50, 50, 50, 255},
+	)
+)
+
+var colorBlind = map[*Theme]*Theme{
+	&Dark:         &DarkColorBlind,
+	&Light:        &LightColorBlind,
+	&HighContrast: &HighContrastColorBlind,
+}
+
+// ColorBlind returns the colour-blind variant of t, or t itself when it has
+// none.
+func (t *Theme) ColorBlind() *Theme {
+	if cb, ok := colorBlind[t]; ok {
+		return cb
+	}
+	return t
+}
+
+func withStatus(t Theme, match, present, miss color.RGBA) Theme {
+	t.Name += "-cb"
+	t.Match = match
+	t.Present = present
+	t.Miss = miss
+	return t
+}
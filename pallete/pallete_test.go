@@ -0,0 +1,111 @@
+package pallete
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+// Machado et al. (2009) matrices for full protanopia, deuteranopia and
+// tritanopia, applied to linear RGB. A nil matrix stands for
+// achromatopsia, where only luminance is left.
+var deficiencies = map[string]*[3][3]float64{
+	"protanopia": {
+		{0.152286, 1.052583, -0.204868},
+		{0.114503, 0.786281, 0.099216},
+		{-0.003882, -0.048116, 1.051998},
+	},
+	"deuteranopia": {
+		{0.367322, 0.860646, -0.227968},
+		{0.280085, 0.672501, 0.047413},
+		{-0.011820, 0.042940, 0.968881},
+	},
+	"tritanopia": {
+		{1.255528, -0.076749, -0.178779},
+		{-0.078411, 0.930809, 0.147602},
+		{0.004733, 0.691367, 0.303900},
+	},
+	"achromatopsia": nil,
+}
+
+// minDistance is the CIE76 ΔE two statuses must keep under every
+// simulation; around 20 reads as clearly different colours.
+const minDistance = 20
+
+func TestColorBlindThemes(t *testing.T) {
+	for _, theme := range []*Theme{&DarkColorBlind, &LightColorBlind, &HighContrastColorBlind} {
+		pairs := [][2]color.RGBA{
+			{theme.Match, theme.Present},
+			{theme.Match, theme.Miss},
+			{theme.Present, theme.Miss},
+		}
+
+		for name, m := range deficiencies {
+			for _, p := range pairs {
+				d := distance(simulate(p[0], m), simulate(p[1], m))
+				if d < minDistance {
+					t.Errorf("%s under %s: %v and %v are %.1f apart, want %d", theme.Name, name, p[0], p[1], d, minDistance)
+				}
+			}
+		}
+	}
+}
+
+func TestColorBlindVariant(t *testing.T) {
+	if Dark.ColorBlind() != &DarkColorBlind {
+		t.Fatal("dark has no colour-blind variant")
+	}
+	if DarkColorBlind.ColorBlind() != &DarkColorBlind {
+		t.Fatal("a variant should be its own colour-blind theme")
+	}
+	if DarkColorBlind.Background != Dark.Background {
+		t.Fatal("the variant changed more than the status colours")
+	}
+}
+
+func linear(c uint8) float64 {
+	v := float64(c) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func simulate(c color.RGBA, m *[3][3]float64) [3]float64 {
+	rgb := [3]float64{linear(c.R), linear(c.G), linear(c.B)}
+
+	if m == nil {
+		y := 0.2126*rgb[0] + 0.7152*rgb[1] + 0.0722*rgb[2]
+		return [3]float64{y, y, y}
+	}
+
+	var out [3]float64
+	for i := range 3 {
+		for j := range 3 {
+			out[i] += m[i][j] * rgb[j]
+		}
+		out[i] = min(1, max(0, out[i]))
+	}
+	return out
+}
+
+// distance is the CIE76 difference of two linear RGB colours in Lab.
+func distance(a, b [3]float64) float64 {
+	la, lb := lab(a), lab(b)
+	return math.Sqrt(math.Pow(la[0]-lb[0], 2) + math.Pow(la[1]-lb[1], 2) + math.Pow(la[2]-lb[2], 2))
+}
+
+func lab(c [3]float64) [3]float64 {
+	x := (0.4124*c[0] + 0.3576*c[1] + 0.1805*c[2]) / 0.95047
+	y := 0.2126*c[0] + 0.7152*c[1] + 0.0722*c[2]
+	z := (0.0193*c[0] + 0.1192*c[1] + 0.9505*c[2]) / 1.08883
+
+	f := func(t float64) float64 {
+		if t > 0.008856 {
+			return math.Cbrt(t)
+		}
+		return 7.787*t + 16.0/116
+	}
+
+	return [3]float64{116*f(y) - 16, 500 * (f(x) - f(y)), 200 * (f(y) - f(z))}
+}
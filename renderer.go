@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// TextRenderer draws letters into cells of LetterWidth×LetterWidth units,
+// each unit s pixels wide, so the bitmap and the TrueType backends can
+// stand in for each other.
+type TextRenderer interface {
+	// DrawLetter draws l in the cell whose top left is at x, y.
+	DrawLetter(screen *ebiten.Image, l rune, x, y, s float32, c color.Color)
+	// Advance is how far the pen moves after l.
+	Advance(l rune, s float32) float32
+}
+
+// Renderer is the backend DrawText and DrawLetter go through.
+var Renderer TextRenderer = BitmapRenderer{}
+
+// Text renderers the settings choose between.
+const (
+	TEXT_PIXEL  = "pixel"
+	TEXT_SMOOTH = "smooth"
+)
+
+var textRenderers = []string{TEXT_PIXEL, TEXT_SMOOTH}
+
+var trueType *TrueTypeRenderer
+
+// SetTextRenderer points Renderer at the backend called name. The TrueType
+// font is only parsed the first time it is picked.
+func SetTextRenderer(name string) error {
+	if name != TEXT_SMOOTH {
+		Renderer = BitmapRenderer{}
+		return nil
+	}
+
+	if trueType == nil {
+		r, err := NewTrueTypeRenderer()
+		if err != nil {
+			return err
+		}
+		trueType = r
+	}
+
+	Renderer = trueType
+	return nil
+}
+
+// BitmapRenderer draws TextFont from the glyph atlas.
+type BitmapRenderer struct{}
+
+func (BitmapRenderer) DrawLetter(screen *ebiten.Image, l rune, x, y, s float32, c color.Color) {
+	drawGlyph(screen, TextFont, l, x, y, s, c)
+}
+
+func (BitmapRenderer) Advance(l rune, s float32) float32 {
+	return float32(TextFont.Glyph(l).Advance) * s
+}
+
+//go:embed fonts/FiraSans-Regular.ttf
+var firaSans []byte
+
+// TrueTypeRenderer draws smooth glyphs from an embedded TrueType font,
+// each centred in the cell a bitmap glyph would take.
+type TrueTypeRenderer struct {
+	source *text.GoTextFaceSource
+	faces  map[float32]*text.GoTextFace
+}
+
+func NewTrueTypeRenderer() (*TrueTypeRenderer, error) {
+	source, err := text.NewGoTextFaceSource(bytes.NewReader(firaSans))
+	if err != nil {
+		return nil, err
+	}
+
+	return &TrueTypeRenderer{
+		source: source,
+		faces:  map[float32]*text.GoTextFace{},
+	}, nil
+}
+
+// face returns the face for scale s. Its size is chosen so lowercase
+// letters come out about as tall as the pixel font's.
+func (r *TrueTypeRenderer) face(s float32) *text.GoTextFace {
+	f, ok := r.faces[s]
+	if !ok {
+		f = &text.GoTextFace{Source: r.source, Size: float64(LetterWidth*s) * 1.25}
+		r.faces[s] = f
+	}
+	return f
+}
+
+func (r *TrueTypeRenderer) DrawLetter(screen *ebiten.Image, l rune, x, y, s float32, c color.Color) {
+	cell := LetterWidth * s
+
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(float64(x+cell/2), float64(y+cell/2))
+	op.ColorScale.ScaleWithColor(c)
+	op.PrimaryAlign = text.AlignCenter
+	op.SecondaryAlign = text.AlignCenter
+
+	drawCalls++
+	text.Draw(screen, string(l), r.face(s), op)
+}
+
+func (r *TrueTypeRenderer) Advance(l rune, s float32) float32 {
+	return LetterWidth * s
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"time"
+	"unicode/utf8"
+)
+
+// Replay files start with replayMagic, then the mode byte, a flags byte,
+// the word as UTF-8 terminated by '\n', and one record per input: the
+// milliseconds since the previous input as a uvarint followed by the input
+// rune in UTF-8. Files from before the flags byte start with replayMagicV1.
+const (
+	replayMagic   = "5LR2"
+	replayMagicV1 = "5LR1"
+)
+
+// replayHardMode is the flag set when the round started in hard mode.
+const replayHardMode = 1
+
+// hardModeInput is recorded when hard mode is switched during a recorded
+// round, which is only possible before the first guess.
+const hardModeInput = '#'
+
+var ErrBadReplay = errors.New("replay: malformed file")
+
+type ReplayEvent struct {
+	At    time.Duration
+	Input rune
+}
+
+type Replay struct {
+	Mode     Mode
+	HardMode bool
+	Word     []rune
+	Events   []ReplayEvent
+}
+
+func NewReplay(mode Mode, word []rune) *Replay {
+	return &Replay{
+		Mode:   mode,
+		Word:   word,
+		Events: make([]ReplayEvent, 0, 64),
+	}
+}
+
+func (r *Replay) MarshalBinary() ([]byte, error) {
+	buf := []byte(replayMagic)
+	buf = append(buf, byte(r.Mode))
+
+	var flags byte
+	if r.HardMode {
+		flags |= replayHardMode
+	}
+	buf = append(buf, flags)
+	buf = append(buf, string(r.Word)...)
+	buf = append(buf, '\n')
+
+	var prev time.Duration
+
+	for _, e := range r.Events {
+		buf = appendEvent(buf, prev, e)
+		prev = e.At
+	}
+
+	return buf, nil
+}
+
+// appendEvent encodes e as one record, timed from the input before it at
+// prev.
+func appendEvent(buf []byte, prev time.Duration, e ReplayEvent) []byte {
+	buf = binary.AppendUvarint(buf, uint64((e.At - prev).Milliseconds()))
+	return utf8.AppendRune(buf, e.Input)
+}
+
+func (r *Replay) UnmarshalBinary(data []byte) error {
+	header := 2
+	switch {
+	case bytes.HasPrefix(data, []byte(replayMagic)):
+		header = 3
+	case !bytes.HasPrefix(data, []byte(replayMagicV1)):
+		return ErrBadReplay
+	}
+
+	if len(data) < len(replayMagic)+header {
+		return ErrBadReplay
+	}
+
+	data = data[len(replayMagic):]
+	r.Mode = Mode(data[0])
+	r.HardMode = false
+	if header == 3 {
+		if data[1]&^replayHardMode != 0 {
+			return ErrBadReplay
+		}
+		r.HardMode = data[1]&replayHardMode != 0
+	}
+	data = data[header-1:]
+
+	if r.Mode != DAILY && r.Mode != ABSURDLE {
+		return ErrBadReplay
+	}
+
+	end := bytes.IndexByte(data, '\n')
+	if end < 0 || !utf8.Valid(data[:end]) {
+		return ErrBadReplay
+	}
+
+	r.Word = []rune(string(data[:end]))
+	if len(r.Word) != wordLength {
+		return ErrBadReplay
+	}
+	data = data[end+1:]
+	r.Events = r.Events[:0]
+
+	var at time.Duration
+
+	for len(data) > 0 {
+		delta, n := binary.Uvarint(data)
+		if n <= 0 {
+			return ErrBadReplay
+		}
+		data = data[n:]
+
+		l, size := utf8.DecodeRune(data)
+		if l == utf8.RuneError {
+			return ErrBadReplay
+		}
+		data = data[size:]
+
+		at += time.Duration(delta) * time.Millisecond
+		r.Events = append(r.Events, ReplayEvent{At: at, Input: l})
+	}
+
+	return nil
+}
+
+func LoadReplay(path string) (*Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Replay{}
+	if err := r.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// NewPlayback creates a game that ignores the player and feeds the recorded
+// inputs back at their original pace.
+func NewPlayback(r *Replay) *Game {
+	g := NewGame(r.Mode)
+	g.Word = r.Word
+	g.Settings.HardMode = r.HardMode
+	g.Playback = r
+
+	return g
+}
+
+// StartRecording records the round to w: the header at once and then every
+// input as it happens, so a round that is abandoned or crashes halfway is
+// still saved.
+func (g *Game) StartRecording(w io.Writer) error {
+	r := NewReplay(g.Mode, g.Word)
+	r.HardMode = g.Settings.HardMode
+
+	header, err := r.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	g.Recording = r
+	g.RecordOut = w
+
+	return nil
+}
+
+func (g *Game) RecordInput(l rune) {
+	if g.Recording == nil {
+		return
+	}
+
+	var prev time.Duration
+	if n := len(g.Recording.Events); n > 0 {
+		prev = g.Recording.Events[n-1].At
+	}
+
+	e := ReplayEvent{At: g.Clock().Sub(g.StartedAt), Input: l}
+	g.Recording.Events = append(g.Recording.Events, e)
+
+	if g.RecordOut == nil {
+		return
+	}
+
+	if _, err := g.RecordOut.Write(appendEvent(nil, prev, e)); err != nil {
+		log.Println(err.Error())
+		g.RecordOut = nil
+	}
+}
+
+func (g *Game) UpdatePlayback() error {
+	elapsed := g.Clock().Sub(g.StartedAt)
+
+	for g.PlaybackIndex < len(g.Playback.Events) {
+		e := g.Playback.Events[g.PlaybackIndex]
+		if e.At > elapsed {
+			break
+		}
+
+		g.PlaybackIndex++
+
+		if e.Input == hardModeInput {
+			g.Settings.HardMode = !g.Settings.HardMode
+			continue
+		}
+
+		if err := g.HandleInput(e.Input); err != nil {
+			return err
+		}
+
+		if g.Stage != GAME {
+			break
+		}
+	}
+
+	return nil
+}
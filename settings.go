@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/e-kucheriavyi/five-letters/pallete"
+)
+
+// THEME_SYSTEM follows the OS light or dark preference.
+const THEME_SYSTEM = "system"
+
+// Settings are the preferences kept between runs.
+type Settings struct {
+	Theme      string `json:"theme"`
+	ColorBlind bool   `json:"color_blind"`
+	Text       string `json:"text"`
+	HardMode   bool   `json:"hard_mode"`
+	Sound      bool   `json:"sound"`
+	Volume     int    `json:"volume"`
+	Haptics    string `json:"haptics"`
+	Layout     string `json:"layout"`
+	Animation  string `json:"animation"`
+}
+
+var DefaultSettings = Settings{
+	Theme:     pallete.Dark.Name,
+	Text:      TEXT_PIXEL,
+	Sound:     true,
+	Volume:    80,
+	Haptics:   HAPTICS_NORMAL,
+	Layout:    LAYOUT_CHARS.String(),
+	Animation: ANIMATION_NORMAL,
+}
+
+const (
+	ANIMATION_NORMAL = "normal"
+	ANIMATION_FAST   = "fast"
+	ANIMATION_OFF    = "off"
+)
+
+var animationSpeeds = []string{ANIMATION_NORMAL, ANIMATION_FAST, ANIMATION_OFF}
+
+// AnimationFactor is how many times faster than normal animations run; 0
+// turns them off.
+func (g *Game) AnimationFactor() int {
+	switch g.Settings.Animation {
+	case ANIMATION_FAST:
+		return 2
+	case ANIMATION_OFF:
+		return 0
+	}
+	return 1
+}
+
+// SettingsPath is where settings are kept, or "" when the platform has no
+// config directory.
+func SettingsPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "five-letters", "settings.json")
+}
+
+// LoadSettings reads the settings at path. A missing file gives the
+// defaults; fields missing from the file keep theirs.
+func LoadSettings(path string) (Settings, error) {
+	s := DefaultSettings
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return DefaultSettings, err
+	}
+
+	return s, nil
+}
+
+func SaveSettings(path string, s Settings) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// ApplySettings makes s the game's settings without saving them. The input
+// layout and text renderer are only switched when s changes them, so the
+// ones picked with -layout and -smooth survive changes to the other
+// settings.
+func (g *Game) ApplySettings(s Settings) {
+	layoutChanged := s.Layout != g.Settings.Layout
+	textChanged := s.Text != g.Settings.Text
+	g.Settings = s
+	g.applyTheme()
+
+	if l, ok := ParseInputLayout(s.Layout); ok && layoutChanged {
+		g.SetInputLayout(l)
+	}
+
+	if textChanged {
+		if err := SetTextRenderer(s.Text); err != nil {
+			log.Println(err.Error())
+		}
+	}
+}
+
+func (g *Game) applyTheme() {
+	g.Theme = ResolveTheme(g.Settings.Theme)
+	if g.Settings.ColorBlind {
+		g.Theme = g.Theme.ColorBlind()
+	}
+}
+
+// SetTheme switches to the theme called name, or THEME_SYSTEM, and saves
+// the choice.
+func (g *Game) SetTheme(name string) {
+	g.Settings.Theme = name
+	g.applyTheme()
+	g.SaveSettings()
+}
+
+// ToggleColorBlind switches the colour-blind palette and tile markers.
+func (g *Game) ToggleColorBlind() {
+	g.Settings.ColorBlind = !g.Settings.ColorBlind
+	g.applyTheme()
+	g.SaveSettings()
+}
+
+// CycleTheme moves to the next built-in theme, then to THEME_SYSTEM, then
+// back to the first.
+func (g *Game) CycleTheme() {
+	g.StepTheme(1)
+}
+
+func (g *Game) StepTheme(step int) {
+	g.SetTheme(stepOption(themeNames(), g.Settings.Theme, step))
+}
+
+// themeNames lists the built-in themes followed by THEME_SYSTEM.
+func themeNames() []string {
+	names := make([]string, 0, len(pallete.Themes)+1)
+	for _, t := range pallete.Themes {
+		names = append(names, t.Name)
+	}
+	return append(names, THEME_SYSTEM)
+}
+
+// stepOption returns the option step places away from current, wrapping
+// around. An unknown current counts as the first option.
+func stepOption(options []string, current string, step int) string {
+	i := 0
+	for j, o := range options {
+		if o == current {
+			i = j
+		}
+	}
+	return options[((i+step)%len(options)+len(options))%len(options)]
+}
+
+func (g *Game) SaveSettings() {
+	if g.SettingsPath == "" {
+		return
+	}
+
+	if err := SaveSettings(g.SettingsPath, g.Settings); err != nil {
+		log.Println(err.Error())
+	}
+}
+
+// ResolveTheme returns the theme for a setting, falling back to dark for
+// names it does not know.
+func ResolveTheme(name string) *pallete.Theme {
+	if name == THEME_SYSTEM {
+		if dark, ok := SystemPrefersDark(); ok && !dark {
+			return &pallete.Light
+		}
+		return &pallete.Dark
+	}
+
+	if t, ok := pallete.ByName(name); ok {
+		return t
+	}
+
+	return &pallete.Dark
+}
+
+// SystemPrefersDark asks the OS whether it is in dark mode. ok is false
+// when the platform gives no answer. The OS is only asked the first time,
+// since that runs an external command.
+var SystemPrefersDark = sync.OnceValues(askSystemPrefersDark)
+
+func askSystemPrefersDark() (dark bool, ok bool) {
+	switch runtime.GOOS {
+	case "darwin":
+		out, err := exec.Command("defaults", "read", "-g", "AppleInterfaceStyle").Output()
+		// The key is missing, and the command fails, in light mode.
+		return err == nil && strings.Contains(string(out), "Dark"), true
+	case "windows":
+		out, err := exec.Command(
+			"reg", "query",
+			`HKCU\Software\Microsoft\Windows\CurrentVersion\Themes\Personalize`,
+			"/v", "AppsUseLightTheme",
+		).Output()
+		if err != nil {
+			return false, false
+		}
+		return strings.Contains(string(out), "0x0"), true
+	case "linux", "freebsd", "openbsd":
+		out, err := exec.Command("gsettings", "get", "org.gnome.desktop.interface", "color-scheme").Output()
+		if err != nil {
+			return false, false
+		}
+		return strings.Contains(string(out), "dark"), true
+	}
+
+	return false, false
+}
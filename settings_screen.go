@@ -0,0 +1,333 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	la "github.com/laranatech/gorana/layout"
+)
+
+// SETTINGS_KEY is the header key that opens the settings screen.
+const SETTINGS_KEY = '*'
+
+type SettingItem byte
+
+const (
+	SETTING_HARD_MODE SettingItem = iota
+	SETTING_THEME
+	SETTING_COLOR_BLIND
+	SETTING_TEXT
+	SETTING_SOUND
+	SETTING_VOLUME
+	SETTING_HAPTICS
+	SETTING_LAYOUT
+	SETTING_ANIMATION
+	SETTING_BACK
+)
+
+const settingCount = int(SETTING_BACK) + 1
+
+// volumeStep is how far one press moves the volume, in percent.
+const volumeStep = 10
+
+var settingLabels = [settingCount]string{
+	"сложный режим",
+	"тема",
+	"для дальтоников",
+	"шрифт",
+	"звук",
+	"громкость",
+	"вибрация",
+	"раскладка",
+	"анимация",
+	"назад",
+}
+
+var settingValueNames = map[string]string{
+	"dark":           "темная",
+	"light":          "светлая",
+	"contrast":       "контраст",
+	THEME_SYSTEM:     "как в системе",
+	"chars":          "символы",
+	"physical":       "йцукен",
+	"translit":       "транслит",
+	ANIMATION_NORMAL: "обычная",
+	ANIMATION_FAST:   "быстрая",
+	ANIMATION_OFF:    "нет",
+	HAPTICS_WEAK:     "слабая",
+	HAPTICS_STRONG:   "сильная",
+	TEXT_PIXEL:       "пиксельный",
+	TEXT_SMOOTH:      "сглаженный",
+}
+
+func CreateSettingsLayout(m Metrics) *la.OutputItem {
+	rowH := min(m.Px(56), (m.H-m.Padding*2-m.Header)/float32(settingCount)-m.Gap)
+
+	rows := make([]*la.NodeItem, 0, settingCount+1)
+	rows = append(rows, la.Node(
+		la.Id("settings_title"),
+		la.Width(la.Grow(1)),
+		la.Height(la.Fix(m.Header)),
+	))
+
+	for i := range settingCount {
+		rows = append(rows, la.Node(
+			la.Id(fmt.Sprintf("setting_%d", i)),
+			la.Width(la.Grow(1)),
+			la.Height(la.Fix(rowH)),
+		))
+	}
+
+	root := la.Node(
+		la.Id("settings"),
+		la.Column(),
+		la.Gap(m.Gap),
+		la.Padding(m.Padding),
+		la.Width(la.Fix(m.W)),
+		la.Height(la.Fix(m.H)),
+		la.Children(rows...),
+	)
+
+	la.Layout(root)
+
+	return la.Export(root)
+}
+
+func (g *Game) OpenSettings() {
+	if g.Stage == SETTINGS {
+		return
+	}
+
+	g.ReturnStage = g.Stage
+	g.Stage = SETTINGS
+	g.SettingsFocus = 0
+	g.Hovered = nil
+	clear(g.Pointers)
+
+	g.Announce("Настройки. " + g.DescribeSetting(SETTING_HARD_MODE))
+}
+
+func (g *Game) CloseSettings() {
+	g.Stage = g.ReturnStage
+	g.Hovered = nil
+	clear(g.Pointers)
+}
+
+func (g *Game) UpdateSettings() error {
+	for _, e := range g.PollEvents() {
+		g.HandleSettingsEvent(e)
+
+		if g.Stage != SETTINGS {
+			break
+		}
+	}
+
+	return nil
+}
+
+// HandleSettingsEvent moves through the settings with up and down, changes
+// the focused one with left, right or activate, and leaves on Escape,
+// gamepad B or the settings key.
+func (g *Game) HandleSettingsEvent(e Event) {
+	switch e.Kind {
+	case EVENT_NAVIGATE:
+		switch e.Direction {
+		case NAV_UP, NAV_PREV_ROW:
+			g.FocusSetting((g.SettingsFocus - 1 + settingCount) % settingCount)
+		case NAV_DOWN, NAV_NEXT_ROW:
+			g.FocusSetting((g.SettingsFocus + 1) % settingCount)
+		case NAV_LEFT:
+			g.ChangeSetting(SettingItem(g.SettingsFocus), -1)
+		case NAV_RIGHT:
+			g.ChangeSetting(SettingItem(g.SettingsFocus), 1)
+		}
+	case EVENT_ACTIVATE, EVENT_SUBMIT:
+		g.ActivateSetting(SettingItem(g.SettingsFocus))
+	case EVENT_CLEAR, EVENT_BACKSPACE, EVENT_SETTINGS:
+		g.CloseSettings()
+	case EVENT_THEME:
+		g.CycleTheme()
+	case EVENT_COLOR_BLIND:
+		g.ToggleColorBlind()
+	case EVENT_POINTER_MOVE:
+		if p, ok := g.Pointers[e.Pointer]; ok {
+			p.X, p.Y = e.X, e.Y
+		}
+		if e.Pointer == MousePointer {
+			g.Hovered = g.findSettingRow(e.X, e.Y)
+		}
+	case EVENT_POINTER_DOWN:
+		row := g.findSettingRow(e.X, e.Y)
+		if row == nil {
+			delete(g.Pointers, e.Pointer)
+			return
+		}
+		g.Pointers[e.Pointer] = &PointerState{Node: row, X: e.X, Y: e.Y}
+	case EVENT_POINTER_UP:
+		p, ok := g.Pointers[e.Pointer]
+		delete(g.Pointers, e.Pointer)
+		if !ok {
+			return
+		}
+
+		p.X, p.Y = e.X, e.Y
+		if p.IsOverNode() {
+			i := settingIndex(p.Node)
+			g.SettingsFocus = i
+			g.ActivateSetting(SettingItem(i))
+		}
+	}
+}
+
+func (g *Game) FocusSetting(i int) {
+	g.SettingsFocus = i
+	g.Announce(g.DescribeSetting(SettingItem(i)))
+}
+
+func (g *Game) ActivateSetting(item SettingItem) {
+	if item == SETTING_BACK {
+		g.CloseSettings()
+		return
+	}
+	g.ChangeSetting(item, 1)
+}
+
+// ChangeSetting moves item step options along, applies it at once and
+// saves it.
+func (g *Game) ChangeSetting(item SettingItem, step int) {
+	switch item {
+	case SETTING_HARD_MODE:
+		if !g.CanChangeHardMode() {
+			g.Announce("Сложный режим меняется только до первой попытки")
+			return
+		}
+		g.Settings.HardMode = !g.Settings.HardMode
+		g.RecordInput(hardModeInput)
+	case SETTING_THEME:
+		g.Settings.Theme = stepOption(themeNames(), g.Settings.Theme, step)
+	case SETTING_COLOR_BLIND:
+		g.Settings.ColorBlind = !g.Settings.ColorBlind
+	case SETTING_TEXT:
+		g.Settings.Text = stepOption(textRenderers, g.Settings.Text, step)
+		if err := SetTextRenderer(g.Settings.Text); err != nil {
+			log.Println(err.Error())
+		}
+	case SETTING_SOUND:
+		g.Settings.Sound = !g.Settings.Sound
+	case SETTING_VOLUME:
+		g.Settings.Volume = min(100, max(0, g.Settings.Volume+step*volumeStep))
+	case SETTING_HAPTICS:
+		g.Settings.Haptics = stepOption(hapticStrengths, g.Settings.Haptics, step)
+	case SETTING_LAYOUT:
+		g.Settings.Layout = stepOption(inputLayoutNames, g.Settings.Layout, step)
+		l, _ := ParseInputLayout(g.Settings.Layout)
+		g.SetInputLayout(l)
+	case SETTING_ANIMATION:
+		g.Settings.Animation = stepOption(animationSpeeds, g.Settings.Animation, step)
+	default:
+		return
+	}
+
+	g.ApplySettings(g.Settings)
+	g.SaveSettings()
+	g.Announce(g.DescribeSetting(item))
+
+	if item == SETTING_SOUND || item == SETTING_VOLUME {
+		g.PlaySound(SOUND_CLICK)
+	}
+	if item == SETTING_HAPTICS {
+		g.Vibrate(HAPTIC_KEY)
+	}
+}
+
+// SettingValue is the value shown next to item's label.
+func (g *Game) SettingValue(item SettingItem) string {
+	switch item {
+	case SETTING_HARD_MODE:
+		return onOff(g.Settings.HardMode)
+	case SETTING_THEME:
+		return settingValueNames[g.Settings.Theme]
+	case SETTING_COLOR_BLIND:
+		return onOff(g.Settings.ColorBlind)
+	case SETTING_TEXT:
+		return settingValueNames[g.Settings.Text]
+	case SETTING_SOUND:
+		return onOff(g.Settings.Sound)
+	case SETTING_VOLUME:
+		return fmt.Sprintf("%d%%", g.Settings.Volume)
+	case SETTING_HAPTICS:
+		return settingValueNames[g.Settings.Haptics]
+	case SETTING_LAYOUT:
+		return settingValueNames[g.Settings.Layout]
+	case SETTING_ANIMATION:
+		return settingValueNames[g.Settings.Animation]
+	}
+	return ""
+}
+
+func (g *Game) DescribeSetting(item SettingItem) string {
+	if v := g.SettingValue(item); v != "" {
+		return settingLabels[item] + ": " + v
+	}
+	return settingLabels[item]
+}
+
+func onOff(v bool) string {
+	if v {
+		return "вкл"
+	}
+	return "выкл"
+}
+
+func (g *Game) findSettingRow(x, y float32) *la.OutputItem {
+	for _, child := range g.SettingsNode.Children {
+		if strings.HasPrefix(child.Id, "setting_") && Collide(child, x, y) {
+			return child
+		}
+	}
+	return nil
+}
+
+func settingIndex(node *la.OutputItem) int {
+	i := 0
+	fmt.Sscanf(node.Id, "setting_%d", &i)
+	return i
+}
+
+func (g *Game) DrawSettings(screen *ebiten.Image) {
+	m := g.Metrics
+	t := g.Theme
+
+	for _, node := range g.SettingsNode.Children {
+		if node.Id == "settings_title" {
+			s := FitScale("настройки", node.W, m.Px(4))
+			DrawTextAligned(screen, "настройки", CenteredIn(node.X, node.Y, node.W, node.H), s, t.Foreground)
+			continue
+		}
+
+		i := settingIndex(node)
+		item := SettingItem(i)
+		pad := m.Px(12)
+
+		fillRect(screen, node.X, node.Y, node.W, node.H, t.Miss)
+
+		label, value := settingLabels[i], g.SettingValue(item)
+		s := FitScale(label+"  "+value, node.W-pad*2, max(1, floor(letterScale(node.H)*0.75)))
+		box := TextBox{X: node.X + pad, Y: node.Y, W: node.W - pad*2, H: node.H, VAlign: ALIGN_CENTER}
+
+		DrawTextAligned(screen, label, box, s, t.Foreground)
+
+		valueColor := t.Match
+		if item == SETTING_HARD_MODE && !g.CanChangeHardMode() {
+			valueColor = t.Passive
+		}
+		box.Align = ALIGN_END
+		DrawTextAligned(screen, value, box, s, valueColor)
+
+		if i == g.SettingsFocus || g.IsHovered(node) || g.IsKeyPressed(node) {
+			strokeRect(screen, node.X, node.Y, node.W, node.H, m.Px(2), t.Focus)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/e-kucheriavyi/five-letters/pallete"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestSettingsScreen(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	h.g.SettingsPath = filepath.Join(t.TempDir(), "settings.json")
+
+	h.typeRunes("ко")
+	h.key(ebiten.KeyF6)
+	if h.g.Stage != SETTINGS {
+		t.Fatalf("F6 left stage %d, want settings", h.g.Stage)
+	}
+
+	h.key(ebiten.KeyArrowDown)
+	h.key(ebiten.KeyArrowRight)
+	if h.g.Theme != &pallete.Light {
+		t.Fatalf("right on theme switched to %s, want light", h.g.Theme.Name)
+	}
+
+	h.key(ebiten.KeyArrowDown)
+	h.key(ebiten.KeyArrowDown)
+	h.key(ebiten.KeyArrowDown)
+	h.key(ebiten.KeyArrowDown)
+	h.key(ebiten.KeyArrowDown)
+	h.key(ebiten.KeyArrowDown)
+	h.key(ebiten.KeyEnter)
+	if h.g.InputLayout != LAYOUT_PHYSICAL {
+		t.Fatalf("layout %v, want physical", h.g.InputLayout)
+	}
+
+	s, err := LoadSettings(h.g.SettingsPath)
+	if err != nil || s.Theme != "light" || s.Layout != "physical" {
+		t.Fatalf("saved %+v, %v; want light theme and physical layout", s, err)
+	}
+
+	h.key(ebiten.KeyEscape)
+	if h.g.Stage != GAME || h.row(0) != "ко" {
+		t.Fatalf("closing settings left stage %d, row %q", h.g.Stage, h.row(0))
+	}
+}
+
+func TestLayoutFlagSurvivesSettings(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	h.g.SetInputLayout(LAYOUT_TRANSLIT)
+
+	h.g.ChangeSetting(SETTING_THEME, 1)
+	h.g.ChangeSetting(SETTING_ANIMATION, 1)
+	if h.g.InputLayout != LAYOUT_TRANSLIT {
+		t.Fatalf("changing other settings switched the layout to %v", h.g.InputLayout)
+	}
+
+	h.g.ChangeSetting(SETTING_LAYOUT, 1)
+	if h.g.InputLayout != LAYOUT_PHYSICAL || h.g.Settings.Layout != "physical" {
+		t.Fatalf("layout row gave %v, want physical", h.g.InputLayout)
+	}
+}
+
+func TestTextRendererSetting(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	h.g.SettingsPath = filepath.Join(t.TempDir(), "settings.json")
+	t.Cleanup(func() { SetTextRenderer(TEXT_PIXEL) })
+
+	h.g.ChangeSetting(SETTING_TEXT, 1)
+	if _, ok := Renderer.(*TrueTypeRenderer); !ok || h.g.SettingValue(SETTING_TEXT) != "сглаженный" {
+		t.Fatalf("renderer %T after picking the smooth font", Renderer)
+	}
+
+	s, err := LoadSettings(h.g.SettingsPath)
+	if err != nil || s.Text != TEXT_SMOOTH {
+		t.Fatalf("saved %+v, %v; want the smooth font", s, err)
+	}
+
+	h.g.ChangeSetting(SETTING_TEXT, 1)
+	if _, ok := Renderer.(BitmapRenderer); !ok {
+		t.Fatalf("renderer %T after picking the pixel font", Renderer)
+	}
+
+	SetTextRenderer(TEXT_SMOOTH)
+	h.g.ChangeSetting(SETTING_THEME, 1)
+	if _, ok := Renderer.(*TrueTypeRenderer); !ok {
+		t.Fatalf("changing the theme dropped the -smooth override for %T", Renderer)
+	}
+}
+
+func TestSettingsPointer(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+
+	h.click("key_*")
+	if h.g.Stage != SETTINGS {
+		t.Fatalf("settings key left stage %d", h.g.Stage)
+	}
+
+	h.click("setting_2")
+	if !h.g.Settings.ColorBlind || h.g.Theme != &pallete.DarkColorBlind {
+		t.Fatal("clicking the colour-blind row did not switch it on")
+	}
+
+	h.click("setting_9")
+	if h.g.Stage != GAME {
+		t.Fatalf("back row left stage %d", h.g.Stage)
+	}
+}
+
+func TestHardMode(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	h.g.ChangeSetting(SETTING_HARD_MODE, 1)
+	if !h.g.Settings.HardMode {
+		t.Fatal("hard mode not switched on before the first guess")
+	}
+
+	h.guess("сазан")
+
+	h.g.ChangeSetting(SETTING_HARD_MODE, 1)
+	if !h.g.Settings.HardMode {
+		t.Fatal("hard mode switched off after the first guess")
+	}
+
+	h.guess("копна")
+	if h.g.LastSubmitted != 0 || h.g.ShakeTimer == 0 {
+		t.Fatalf("guess without found letters accepted: submitted %d", h.g.LastSubmitted)
+	}
+
+	if got := h.g.HardModeViolation([]rune("кабан")); got != "3 буква должна быть з" {
+		t.Fatalf("violation %q", got)
+	}
+}
+
+func TestAnimationOff(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	h.g.Settings.Animation = ANIMATION_OFF
+
+	h.guess("ко")
+	if h.g.ShakeTimer != 0 {
+		t.Fatalf("shake %d with animation off", h.g.ShakeTimer)
+	}
+}
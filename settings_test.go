@@ -0,0 +1,77 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/e-kucheriavyi/five-letters/pallete"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestSettingsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "settings.json")
+
+	s, err := LoadSettings(path)
+	if err != nil || s != DefaultSettings {
+		t.Fatalf("missing file = %+v, %v; want defaults", s, err)
+	}
+
+	s.Theme = pallete.Light.Name
+	if err := SaveSettings(path, s); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadSettings(path)
+	if err != nil || got != s {
+		t.Fatalf("loaded %+v, %v; want %+v", got, err, s)
+	}
+}
+
+func TestCycleThemePersists(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	h.g.SettingsPath = filepath.Join(t.TempDir(), "settings.json")
+
+	if h.g.Theme != &pallete.Dark {
+		t.Fatalf("default theme %s, want dark", h.g.Theme.Name)
+	}
+
+	h.key(ebiten.KeyF3)
+	if h.g.Theme != &pallete.Light {
+		t.Fatalf("F3 switched to %s, want light", h.g.Theme.Name)
+	}
+
+	h.hold(ebiten.KeyF3, 40)
+	if h.g.Theme != &pallete.HighContrast {
+		t.Fatalf("held F3 switched to %s, want a single step to contrast", h.g.Theme.Name)
+	}
+
+	s, err := LoadSettings(h.g.SettingsPath)
+	if err != nil || s.Theme != pallete.HighContrast.Name {
+		t.Fatalf("saved %+v, %v; want the contrast theme", s, err)
+	}
+
+	h.key(ebiten.KeyF3)
+	h.key(ebiten.KeyF3)
+	if h.g.Settings.Theme != pallete.Dark.Name {
+		t.Fatalf("cycling wrapped to %q, want dark", h.g.Settings.Theme)
+	}
+}
+
+func TestSystemTheme(t *testing.T) {
+	prev := SystemPrefersDark
+	defer func() { SystemPrefersDark = prev }()
+
+	SystemPrefersDark = func() (bool, bool) { return false, true }
+	if got := ResolveTheme(THEME_SYSTEM); got != &pallete.Light {
+		t.Fatalf("light OS gave %s", got.Name)
+	}
+
+	SystemPrefersDark = func() (bool, bool) { return false, false }
+	if got := ResolveTheme(THEME_SYSTEM); got != &pallete.Dark {
+		t.Fatalf("unknown OS preference gave %s, want dark", got.Name)
+	}
+
+	if got := ResolveTheme("sepia"); got != &pallete.Dark {
+		t.Fatalf("unknown theme gave %s, want dark", got.Name)
+	}
+}
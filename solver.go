@@ -0,0 +1,263 @@
+package main
+
+import (
+	"sync"
+	"unicode/utf8"
+)
+
+const wordLength = 5
+
+// Pattern is the feedback for a whole word packed in base 3, one digit per
+// letter, using the LetterStatus values WRONG, PRESENT and GUESSED.
+type Pattern uint8
+
+const solvedPattern Pattern = 242
+
+// At returns the status of the letter at position i.
+func (p Pattern) At(i int) LetterStatus {
+	for range wordLength - 1 - i {
+		p /= 3
+	}
+	return LetterStatus(p % 3)
+}
+
+type solverWord struct {
+	runes [wordLength]rune
+	mask  uint64
+}
+
+type GuessAnalysis struct {
+	Guess     []rune
+	Before    int
+	Remaining int
+	Best      []rune
+	Skill     int
+}
+
+var (
+	solverWords     []solverWord
+	solverWordsOnce sync.Once
+	openingBest     solverWord
+	openingOnce     sync.Once
+)
+
+// letterBit is a cheap pre-check for Contains; Cyrillic letters never share
+// a bit, other runes may.
+func letterBit(l rune) uint64 {
+	return 1 << (uint(l) % 64)
+}
+
+func newSolverWord(w []rune) solverWord {
+	sw := solverWord{}
+	for i := range wordLength {
+		sw.runes[i] = w[i]
+		sw.mask |= letterBit(w[i])
+	}
+	return sw
+}
+
+func SolverWords() []solverWord {
+	solverWordsOnce.Do(func() {
+		words := Dictionary()
+		solverWords = make([]solverWord, 0, len(words))
+
+		for _, w := range words {
+			if utf8.RuneCountInString(w) != wordLength {
+				continue
+			}
+			solverWords = append(solverWords, newSolverWord([]rune(w)))
+		}
+	})
+
+	return solverWords
+}
+
+// Score returns the feedback the game shows for guess when word is hidden.
+// It follows GetLetterStatus: a letter is PRESENT whenever the hidden word
+// contains it, regardless of how many times.
+func Score(guess, word solverWord) Pattern {
+	p := Pattern(0)
+
+	for i := range wordLength {
+		p *= 3
+
+		if guess.runes[i] == word.runes[i] {
+			p += Pattern(GUESSED)
+		} else if word.mask&letterBit(guess.runes[i]) != 0 && word.Contains(guess.runes[i]) {
+			p += Pattern(PRESENT)
+		}
+	}
+
+	return p
+}
+
+func (w solverWord) Contains(l rune) bool {
+	for _, c := range w.runes {
+		if c == l {
+			return true
+		}
+	}
+	return false
+}
+
+func Filter(candidates []solverWord, guess solverWord, p Pattern) []solverWord {
+	out := make([]solverWord, 0, len(candidates))
+
+	for _, c := range candidates {
+		if Score(guess, c) == p {
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
+
+// FilterHint keeps the candidates the hint allows.
+func FilterHint(candidates []solverWord, h Hint) []solverWord {
+	out := make([]solverWord, 0, len(candidates))
+
+	for _, c := range candidates {
+		if h.Allows(c) {
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
+
+// LargestBucket splits candidates by the feedback guess would get and
+// returns the biggest group. Ties go to the pattern with the lowest value,
+// which gives away the fewest letters and never prefers a solve.
+func LargestBucket(guess solverWord, candidates []solverWord) (Pattern, []solverWord) {
+	var buckets [243]int
+
+	for _, c := range candidates {
+		buckets[Score(guess, c)]++
+	}
+
+	best := Pattern(0)
+	for p, n := range buckets {
+		if n > buckets[best] {
+			best = Pattern(p)
+		}
+	}
+
+	return best, Filter(candidates, guess, best)
+}
+
+// ExpectedRemaining is the average number of candidates left after guess,
+// assuming every candidate is equally likely to be the hidden word.
+func ExpectedRemaining(guess solverWord, candidates []solverWord) float64 {
+	if len(candidates) == 0 {
+		return 0
+	}
+
+	var buckets [243]int
+
+	for _, c := range candidates {
+		buckets[Score(guess, c)]++
+	}
+
+	sum := 0
+	for p, n := range buckets {
+		if Pattern(p) == solvedPattern {
+			continue
+		}
+		sum += n * n
+	}
+
+	return float64(sum) / float64(len(candidates))
+}
+
+// BestGuess picks the dictionary word that minimises ExpectedRemaining,
+// preferring remaining candidates on ties.
+func BestGuess(candidates []solverWord) (solverWord, float64) {
+	if len(candidates) == 0 {
+		return solverWord{}, 0
+	}
+
+	if len(candidates) == 1 {
+		return candidates[0], 0
+	}
+
+	words := SolverWords()
+	if len(candidates) == len(words) {
+		openingOnce.Do(func() {
+			openingBest, _ = bestGuessFrom(words, candidates)
+		})
+		return openingBest, ExpectedRemaining(openingBest, candidates)
+	}
+
+	best, score := bestGuessFrom(candidates, candidates)
+	other, otherScore := bestGuessFrom(words, candidates)
+
+	if otherScore < score {
+		return other, otherScore
+	}
+
+	return best, score
+}
+
+func bestGuessFrom(pool, candidates []solverWord) (solverWord, float64) {
+	best := pool[0]
+	bestScore := ExpectedRemaining(best, candidates)
+
+	for _, w := range pool[1:] {
+		s := ExpectedRemaining(w, candidates)
+		if s < bestScore {
+			best = w
+			bestScore = s
+		}
+	}
+
+	return best, bestScore
+}
+
+// Analyze replays the submitted guesses against word and reports, for each
+// of them, how far it narrowed the dictionary compared to the solver. Hints
+// narrow the candidates before the guess that followed them, so the player
+// and the solver are judged on the same knowledge.
+func Analyze(guesses [][]rune, word []rune, hints []Hint) []GuessAnalysis {
+	hidden := newSolverWord(word)
+	candidates := SolverWords()
+	out := make([]GuessAnalysis, 0, len(guesses))
+
+	for i, g := range guesses {
+		for len(hints) > 0 && hints[0].Turn <= i {
+			candidates = FilterHint(candidates, hints[0])
+			hints = hints[1:]
+		}
+
+		if len(g) != wordLength {
+			continue
+		}
+
+		guess := newSolverWord(g)
+		best, bestScore := BestGuess(candidates)
+		score := ExpectedRemaining(guess, candidates)
+		p := Score(guess, hidden)
+		remaining := Filter(candidates, guess, p)
+
+		out = append(out, GuessAnalysis{
+			Guess:     g,
+			Before:    len(candidates),
+			Remaining: len(remaining),
+			Best:      best.runes[:],
+			Skill:     skill(score, bestScore),
+		})
+
+		candidates = remaining
+	}
+
+	return out
+}
+
+// skill maps the player's expected remaining candidates onto 0..100, where
+// 100 means the guess was as good as the solver's.
+func skill(score, best float64) int {
+	if score <= best {
+		return 100
+	}
+
+	return int(100 * (best + 1) / (score + 1))
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+type Sound byte
+
+const (
+	SOUND_CLICK Sound = iota
+	SOUND_ERROR
+	SOUND_TILE_GUESSED
+	SOUND_TILE_PRESENT
+	SOUND_TILE_WRONG
+	SOUND_WIN
+	SOUND_LOSE
+)
+
+const soundCount = int(SOUND_LOSE) + 1
+
+// Speaker plays sound effects at volume between 0 and 1. Tests swap it for
+// one that only records what was played.
+type Speaker interface {
+	Play(s Sound, volume float64)
+}
+
+// revealDelay is the gap between the tones of a submitted row's tiles.
+const revealDelay = 120 * time.Millisecond
+
+var tileSounds = map[LetterStatus]Sound{
+	GUESSED: SOUND_TILE_GUESSED,
+	PRESENT: SOUND_TILE_PRESENT,
+	WRONG:   SOUND_TILE_WRONG,
+}
+
+// QueuedSound waits in the game's sound queue until At.
+type QueuedSound struct {
+	Sound Sound
+	At    time.Time
+}
+
+// PlaySound plays s now, unless the sound is muted.
+func (g *Game) PlaySound(s Sound) {
+	if g.Speaker == nil || !g.Settings.Sound || g.Settings.Volume <= 0 {
+		return
+	}
+
+	g.Speaker.Play(s, float64(g.Settings.Volume)/100)
+}
+
+// QueueRevealSounds plays a tone per tile of submitted row r, one after
+// another, and then the win or lose jingle if the round is over. Rows
+// submitted quickly wait for the previous row's tones to finish.
+func (g *Game) QueueRevealSounds(r int) {
+	at := g.Clock()
+	if n := len(g.SoundQueue); n > 0 {
+		at = later(at, g.SoundQueue[n-1].At.Add(revealDelay))
+	}
+
+	for i, l := range g.GuessedWords[r] {
+		g.SoundQueue = append(g.SoundQueue, QueuedSound{Sound: tileSounds[g.GetLetterStatus(r, i, l)], At: at})
+		at = at.Add(revealDelay)
+	}
+
+	if g.Stage != SCORE {
+		return
+	}
+
+	jingle := SOUND_LOSE
+	if g.IsWordGuessed() {
+		jingle = SOUND_WIN
+	}
+	g.SoundQueue = append(g.SoundQueue, QueuedSound{Sound: jingle, At: at})
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// UpdateSounds plays the queued sounds that are due.
+func (g *Game) UpdateSounds() {
+	now := g.Clock()
+
+	n := 0
+	for n < len(g.SoundQueue) && !g.SoundQueue[n].At.After(now) {
+		g.PlaySound(g.SoundQueue[n].Sound)
+		n++
+	}
+
+	g.SoundQueue = g.SoundQueue[n:]
+}
+
+const sampleRate = 44100
+
+// AudioSpeaker plays through ebiten's audio context. Every sound is
+// synthesised once, when the speaker is made.
+type AudioSpeaker struct {
+	context *audio.Context
+	samples [soundCount][]byte
+}
+
+func NewAudioSpeaker() *AudioSpeaker {
+	context := audio.CurrentContext()
+	if context == nil {
+		context = audio.NewContext(sampleRate)
+	}
+
+	s := &AudioSpeaker{context: context}
+	for i, notes := range soundNotes {
+		s.samples[i] = Synthesize(context.SampleRate(), notes)
+	}
+
+	return s
+}
+
+func (s *AudioSpeaker) Play(sound Sound, volume float64) {
+	p := s.context.NewPlayerF32FromBytes(s.samples[sound])
+	p.SetVolume(volume)
+	p.Play()
+}
+
+// Note is a single tone: a sine, or a square wave for harsher sounds.
+type Note struct {
+	Freq     float64
+	Duration time.Duration
+	Square   bool
+}
+
+var soundNotes = [soundCount][]Note{
+	SOUND_CLICK: {{Freq: 1400, Duration: 20 * time.Millisecond}},
+	SOUND_ERROR: {
+		{Freq: 110, Duration: 120 * time.Millisecond, Square: true},
+		{Freq: 98, Duration: 160 * time.Millisecond, Square: true},
+	},
+	SOUND_TILE_GUESSED: {{Freq: 880, Duration: 90 * time.Millisecond}},
+	SOUND_TILE_PRESENT: {{Freq: 659.26, Duration: 90 * time.Millisecond}},
+	SOUND_TILE_WRONG:   {{Freq: 440, Duration: 90 * time.Millisecond}},
+	SOUND_WIN: {
+		{Freq: 523.25, Duration: 110 * time.Millisecond},
+		{Freq: 659.26, Duration: 110 * time.Millisecond},
+		{Freq: 783.99, Duration: 110 * time.Millisecond},
+		{Freq: 1046.5, Duration: 300 * time.Millisecond},
+	},
+	SOUND_LOSE: {
+		{Freq: 392, Duration: 180 * time.Millisecond},
+		{Freq: 311.13, Duration: 180 * time.Millisecond},
+		{Freq: 261.63, Duration: 400 * time.Millisecond},
+	},
+}
+
+// Synthesize renders notes back to back as 32-bit float stereo samples,
+// the format ebiten's F32 players take. Each note fades in over a few
+// milliseconds and out to silence, so they join without clicks.
+func Synthesize(rate int, notes []Note) []byte {
+	attack := float64(rate) * 0.005
+	buf := []byte{}
+
+	for _, n := range notes {
+		count := int(n.Duration.Seconds() * float64(rate))
+
+		for i := range count {
+			wave := math.Sin(2 * math.Pi * n.Freq * float64(i) / float64(rate))
+			amp := 0.3
+			if n.Square {
+				wave = math.Copysign(1, wave)
+				amp = 0.15
+			}
+
+			left := 1 - float64(i)/float64(count)
+			v := math.Float32bits(float32(wave * amp * min(1, float64(i)/attack) * left * left))
+
+			buf = binary.LittleEndian.AppendUint32(buf, v)
+			buf = binary.LittleEndian.AppendUint32(buf, v)
+		}
+	}
+
+	return buf
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/binary"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// fakeSpeaker records what was played instead of playing it.
+type fakeSpeaker struct {
+	played  []Sound
+	volumes []float64
+}
+
+func (s *fakeSpeaker) Play(sound Sound, volume float64) {
+	s.played = append(s.played, sound)
+	s.volumes = append(s.volumes, volume)
+}
+
+func TestSoundEffects(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	speaker := &fakeSpeaker{}
+	h.g.Speaker = speaker
+
+	h.typeRunes("ко")
+	h.key(ebiten.KeyEnter)
+	want := []Sound{SOUND_CLICK, SOUND_CLICK, SOUND_CLICK, SOUND_ERROR}
+	if !slices.Equal(speaker.played, want) {
+		t.Fatalf("played %v, want %v", speaker.played, want)
+	}
+
+	h.key(ebiten.KeyEscape)
+	speaker.played = nil
+	h.guess("сазан")
+	h.wait(time.Second)
+	want = []Sound{
+		SOUND_CLICK, SOUND_CLICK, SOUND_CLICK, SOUND_CLICK, SOUND_CLICK, SOUND_CLICK,
+		SOUND_TILE_WRONG, SOUND_TILE_GUESSED, SOUND_TILE_GUESSED, SOUND_TILE_PRESENT, SOUND_TILE_GUESSED,
+	}
+	if !slices.Equal(speaker.played, want) {
+		t.Fatalf("played %v, want %v", speaker.played, want)
+	}
+
+	speaker.played = nil
+	h.guess("вазон")
+	h.wait(time.Second)
+	if got := speaker.played[len(speaker.played)-1]; got != SOUND_WIN {
+		t.Fatalf("round won with %v, want the win jingle", got)
+	}
+	if speaker.volumes[0] != 0.8 {
+		t.Fatalf("default volume %v, want 0.8", speaker.volumes[0])
+	}
+}
+
+func TestRevealSoundsAreSpaced(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	h.g.Speaker = &fakeSpeaker{}
+
+	for _, w := range []string{"копна", "сазан", "копна", "сазан", "копна", "сазан"} {
+		h.guess(w)
+	}
+
+	q := h.g.SoundQueue
+	if len(q) < wordLength+1 || q[len(q)-1].Sound != SOUND_LOSE {
+		t.Fatalf("queued %v, want the last row's tiles and the lose jingle", q)
+	}
+	for i := 1; i < len(q); i++ {
+		if d := q[i].At.Sub(q[i-1].At); d != revealDelay {
+			t.Fatalf("sound %d comes %v after the previous, want %v", i, d, revealDelay)
+		}
+	}
+}
+
+func TestMute(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	speaker := &fakeSpeaker{}
+	h.g.Speaker = speaker
+
+	h.g.Settings.Sound = false
+	h.guess("ко")
+	if len(speaker.played) != 0 {
+		t.Fatalf("muted game played %v", speaker.played)
+	}
+
+	h.g.Settings.Sound = true
+	h.g.Settings.Volume = 0
+	h.guess("ко")
+	if len(speaker.played) != 0 {
+		t.Fatalf("zero volume played %v", speaker.played)
+	}
+}
+
+func TestVolumeSetting(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	speaker := &fakeSpeaker{}
+	h.g.Speaker = speaker
+
+	h.g.OpenSettings()
+	h.g.ChangeSetting(SETTING_VOLUME, 1)
+	h.g.ChangeSetting(SETTING_VOLUME, 1)
+	if h.g.Settings.Volume != 100 || h.g.SettingValue(SETTING_VOLUME) != "100%" {
+		t.Fatalf("volume %d, want it to stop at 100", h.g.Settings.Volume)
+	}
+
+	for range 12 {
+		h.g.ChangeSetting(SETTING_VOLUME, -1)
+	}
+	if h.g.Settings.Volume != 0 {
+		t.Fatalf("volume %d, want it to stop at 0", h.g.Settings.Volume)
+	}
+
+	if !slices.Equal(speaker.volumes[:2], []float64{0.9, 1}) {
+		t.Fatalf("previewed at %v, want the new volume", speaker.volumes)
+	}
+}
+
+func TestSynthesize(t *testing.T) {
+	b := Synthesize(1000, []Note{
+		{Freq: 100, Duration: 100 * time.Millisecond},
+		{Freq: 50, Duration: 50 * time.Millisecond, Square: true},
+	})
+
+	if len(b) != 150*8 {
+		t.Fatalf("got %d bytes, want 150 stereo float32 frames", len(b))
+	}
+
+	for i := 0; i < len(b); i += 4 {
+		v := math.Float32frombits(binary.LittleEndian.Uint32(b[i:]))
+		if v < -1 || v > 1 {
+			t.Fatalf("sample %d = %v, out of range", i/4, v)
+		}
+	}
+
+	if first := math.Float32frombits(binary.LittleEndian.Uint32(b)); first != 0 {
+		t.Fatalf("first sample %v, want it to fade in from silence", first)
+	}
+}
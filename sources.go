@@ -0,0 +1,453 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type EventKind byte
+
+const (
+	EVENT_LETTER EventKind = iota
+	EVENT_BACKSPACE
+	EVENT_CLEAR
+	EVENT_SUBMIT
+	EVENT_HINT
+	EVENT_NAVIGATE
+	EVENT_ACTIVATE
+	EVENT_POINTER_MOVE
+	EVENT_POINTER_DOWN
+	EVENT_POINTER_UP
+	EVENT_GAMEPAD_CONNECTED
+	EVENT_THEME
+	EVENT_COLOR_BLIND
+	EVENT_DESCRIBE
+	EVENT_SETTINGS
+	EVENT_COMPOSE
+	EVENT_SHARE
+)
+
+type Direction byte
+
+const (
+	NAV_UP Direction = iota
+	NAV_DOWN
+	NAV_LEFT
+	NAV_RIGHT
+	NAV_PREV_ROW
+	NAV_NEXT_ROW
+)
+
+// MousePointer is the pointer id of the mouse; touches use their TouchID.
+const MousePointer = -1
+
+// Event is a semantic input the game reacts to, independent of the device
+// that produced it. Rune is set for letters and hints, Direction for
+// navigation, Pointer, X and Y for pointer events, and Text and Replace
+// for composed input: Text takes the place of the last Replace letters of
+// the same composition.
+type Event struct {
+	Kind      EventKind
+	Rune      rune
+	Direction Direction
+	Pointer   int
+	X         float32
+	Y         float32
+	Text      string
+	Replace   int
+}
+
+// Input returns the rune HandleInput expects for key-like events.
+func (e Event) Input() rune {
+	switch e.Kind {
+	case EVENT_BACKSPACE:
+		return '-'
+	case EVENT_CLEAR:
+		return '_'
+	case EVENT_SUBMIT:
+		return '+'
+	}
+	return e.Rune
+}
+
+func RuneEvent(l rune) Event {
+	switch l {
+	case '-':
+		return Event{Kind: EVENT_BACKSPACE}
+	case '_':
+		return Event{Kind: EVENT_CLEAR}
+	case '+':
+		return Event{Kind: EVENT_SUBMIT}
+	case '?', '!':
+		return Event{Kind: EVENT_HINT, Rune: l}
+	}
+	return Event{Kind: EVENT_LETTER, Rune: l}
+}
+
+// InputSource turns one kind of device into events. Poll is called once per
+// tick and appends whatever happened since the previous call.
+type InputSource interface {
+	Poll(events []Event) []Event
+}
+
+func DefaultSources(d Device, layout InputLayout, repeat KeyRepeat) []InputSource {
+	return []InputSource{
+		&KeyboardSource{Device: d, Layout: layout, Repeat: repeat},
+		&MouseSource{Device: d},
+		&TouchSource{Device: d},
+		&GamepadSource{Device: d},
+	}
+}
+
+// KeyRepeat makes a held key fire again after Delay ticks and then every
+// Rate ticks. A zero Rate turns repeating off.
+type KeyRepeat struct {
+	Delay int
+	Rate  int
+}
+
+var DefaultKeyRepeat = KeyRepeat{Delay: 30, Rate: 4}
+
+// KeyRepeatFromTime converts a delay in milliseconds and a rate in repeats
+// per second into ticks.
+func KeyRepeatFromTime(delayMs, perSecond int) KeyRepeat {
+	tps := ebiten.TPS()
+	r := KeyRepeat{Delay: max(1, delayMs*tps/1000)}
+
+	if perSecond > 0 {
+		r.Rate = max(1, tps/perSecond)
+	}
+
+	return r
+}
+
+// Fires reports whether a key held for d ticks acts on this tick.
+func (r KeyRepeat) Fires(d int, repeats bool) bool {
+	if d == 1 {
+		return true
+	}
+
+	if !repeats || r.Rate <= 0 || d < r.Delay {
+		return false
+	}
+
+	return (d-r.Delay)%r.Rate == 0
+}
+
+// KeyboardSource reads letters according to Layout. Enter, Backspace, hint,
+// theme and arrow keys work by position in every layout. Keys act when pressed;
+// Backspace, arrows and physical letters repeat while held, while
+// character input already comes repeated by the OS.
+type KeyboardSource struct {
+	Device   Device
+	Layout   InputLayout
+	Repeat   KeyRepeat
+	translit Transliterator
+}
+
+var navigationKeys = map[ebiten.Key]Direction{
+	ebiten.KeyArrowUp:    NAV_UP,
+	ebiten.KeyArrowDown:  NAV_DOWN,
+	ebiten.KeyArrowLeft:  NAV_LEFT,
+	ebiten.KeyArrowRight: NAV_RIGHT,
+}
+
+var settingKeys = map[ebiten.Key]EventKind{
+	ebiten.KeyF3: EVENT_THEME,
+	ebiten.KeyF4: EVENT_COLOR_BLIND,
+	ebiten.KeyF5: EVENT_DESCRIBE,
+	ebiten.KeyF6: EVENT_SETTINGS,
+	ebiten.KeyF7: EVENT_SHARE,
+}
+
+func (s *KeyboardSource) Poll(events []Event) []Event {
+	switch s.Layout {
+	case LAYOUT_CHARS:
+		for _, c := range s.Device.InputChars() {
+			l := NormalizeLetter(c)
+			if IsAlphabetLetter(l) {
+				events = append(events, RuneEvent(l))
+			}
+		}
+	case LAYOUT_TRANSLIT:
+		for _, c := range s.Device.InputChars() {
+			events = s.translit.Feed(c, events)
+		}
+	}
+
+	keys := s.Device.PressedKeys()
+	ctrl := false
+
+	for _, key := range keys {
+		if key == ebiten.KeyControlLeft || key == ebiten.KeyControlRight {
+			ctrl = true
+		}
+	}
+
+	for _, key := range keys {
+		d := s.Device.KeyPressDuration(key)
+
+		if kind, ok := settingKeys[key]; ok {
+			if d == 1 {
+				events = append(events, Event{Kind: kind})
+			}
+			continue
+		}
+
+		if dir, ok := navigationKeys[key]; ok {
+			if s.Repeat.Fires(d, true) {
+				events = append(events, Event{Kind: EVENT_NAVIGATE, Direction: dir})
+			}
+			continue
+		}
+
+		l := MapInputToRune(key)
+		if l == ' ' && key != ebiten.KeyEscape && key != ebiten.KeyEnter {
+			continue
+		}
+
+		if IsAlphabetLetter(l) && s.Layout != LAYOUT_PHYSICAL {
+			continue
+		}
+
+		if !s.Repeat.Fires(d, l == '-' || IsAlphabetLetter(l)) {
+			continue
+		}
+
+		s.translit.Reset()
+
+		switch {
+		case key == ebiten.KeyEnter:
+			events = append(events, Event{Kind: EVENT_ACTIVATE})
+		case key == ebiten.KeyEscape, l == '-' && ctrl:
+			events = append(events, Event{Kind: EVENT_CLEAR})
+		default:
+			events = append(events, RuneEvent(l))
+		}
+	}
+
+	return events
+}
+
+// MouseSource reports the mouse as MousePointer. It ignores moves and
+// presses while the screen is touched, since some platforms also move the
+// cursor for touches, but a button pressed before the touch is still
+// released, at the last position the mouse itself reported.
+type MouseSource struct {
+	Device Device
+	x, y   int
+	down   bool
+}
+
+func (s *MouseSource) Poll(events []Event) []Event {
+	released := s.Device.IsMouseButtonJustReleased(ebiten.MouseButton0)
+
+	if len(s.Device.TouchIDs()) > 0 {
+		if released && s.down {
+			s.down = false
+			events = append(events, s.event(EVENT_POINTER_UP))
+		}
+		return events
+	}
+
+	x, y := s.Device.CursorPosition()
+
+	if x != s.x || y != s.y {
+		s.x, s.y = x, y
+		events = append(events, s.event(EVENT_POINTER_MOVE))
+	}
+
+	if s.Device.IsMouseButtonJustPressed(ebiten.MouseButton0) {
+		s.down = true
+		events = append(events, s.event(EVENT_POINTER_DOWN))
+	}
+
+	if released {
+		s.down = false
+		events = append(events, s.event(EVENT_POINTER_UP))
+	}
+
+	return events
+}
+
+func (s *MouseSource) event(kind EventKind) Event {
+	return Event{
+		Kind:    kind,
+		Pointer: MousePointer,
+		X:       float32(s.x),
+		Y:       float32(s.y),
+	}
+}
+
+// TouchSource reports every touch by id, so lifting one finger ends only
+// that touch. A released touch has no position any more, so its last one
+// is remembered.
+type TouchSource struct {
+	Device  Device
+	touches map[ebiten.TouchID][2]int
+}
+
+func (s *TouchSource) Poll(events []Event) []Event {
+	if s.touches == nil {
+		s.touches = map[ebiten.TouchID][2]int{}
+	}
+
+	for _, id := range s.Device.JustPressedTouchIDs() {
+		x, y := s.Device.TouchPosition(id)
+		s.touches[id] = [2]int{x, y}
+		events = append(events, touchEvent(EVENT_POINTER_DOWN, id, x, y))
+	}
+
+	for _, id := range s.Device.TouchIDs() {
+		prev, ok := s.touches[id]
+		if !ok {
+			continue
+		}
+
+		x, y := s.Device.TouchPosition(id)
+		if prev[0] != x || prev[1] != y {
+			s.touches[id] = [2]int{x, y}
+			events = append(events, touchEvent(EVENT_POINTER_MOVE, id, x, y))
+		}
+	}
+
+	for _, id := range s.Device.JustReleasedTouchIDs() {
+		p, ok := s.touches[id]
+		if !ok {
+			continue
+		}
+		delete(s.touches, id)
+		events = append(events, touchEvent(EVENT_POINTER_UP, id, p[0], p[1]))
+	}
+
+	return events
+}
+
+func touchEvent(kind EventKind, id ebiten.TouchID, x, y int) Event {
+	return Event{
+		Kind:    kind,
+		Pointer: int(id),
+		X:       float32(x),
+		Y:       float32(y),
+	}
+}
+
+const (
+	stickDeadZone    = 0.5
+	stickRepeatDelay = 18
+	stickRepeatRate  = 6
+)
+
+// GamepadSource reads gamepads with the standard layout. The D-pad and the
+// left stick move the keyboard focus, the shoulders jump to the first key of
+// the previous or next row, A presses the focused key, B deletes, X and Start
+// submit.
+type GamepadSource struct {
+	Device Device
+	sticks map[ebiten.GamepadID]*stickState
+}
+
+// stickState makes a held stick repeat like a held key, counted in ticks.
+type stickState struct {
+	direction Direction
+	held      bool
+	ticks     int
+}
+
+var gamepadButtonEvents = map[ebiten.StandardGamepadButton]Event{
+	ebiten.StandardGamepadButtonLeftTop:       {Kind: EVENT_NAVIGATE, Direction: NAV_UP},
+	ebiten.StandardGamepadButtonLeftBottom:    {Kind: EVENT_NAVIGATE, Direction: NAV_DOWN},
+	ebiten.StandardGamepadButtonLeftLeft:      {Kind: EVENT_NAVIGATE, Direction: NAV_LEFT},
+	ebiten.StandardGamepadButtonLeftRight:     {Kind: EVENT_NAVIGATE, Direction: NAV_RIGHT},
+	ebiten.StandardGamepadButtonFrontTopLeft:  {Kind: EVENT_NAVIGATE, Direction: NAV_PREV_ROW},
+	ebiten.StandardGamepadButtonFrontTopRight: {Kind: EVENT_NAVIGATE, Direction: NAV_NEXT_ROW},
+	ebiten.StandardGamepadButtonRightBottom:   {Kind: EVENT_ACTIVATE},
+	ebiten.StandardGamepadButtonRightRight:    {Kind: EVENT_BACKSPACE},
+	ebiten.StandardGamepadButtonRightLeft:     {Kind: EVENT_SUBMIT},
+	ebiten.StandardGamepadButtonCenterRight:   {Kind: EVENT_SUBMIT},
+}
+
+func (s *GamepadSource) Poll(events []Event) []Event {
+	if s.sticks == nil {
+		s.sticks = map[ebiten.GamepadID]*stickState{}
+	}
+
+	for _, id := range s.Device.JustConnectedGamepadIDs() {
+		s.sticks[id] = &stickState{}
+		events = append(events, Event{Kind: EVENT_GAMEPAD_CONNECTED})
+	}
+
+	for id := range s.sticks {
+		if s.Device.IsGamepadJustDisconnected(id) {
+			delete(s.sticks, id)
+		}
+	}
+
+	for _, id := range s.Device.GamepadIDs() {
+		for _, b := range s.Device.JustPressedGamepadButtons(id) {
+			if e, ok := gamepadButtonEvents[b]; ok {
+				events = append(events, e)
+			}
+		}
+
+		events = s.pollStick(id, events)
+	}
+
+	return events
+}
+
+func (s *GamepadSource) pollStick(id ebiten.GamepadID, events []Event) []Event {
+	st, ok := s.sticks[id]
+	if !ok {
+		st = &stickState{}
+		s.sticks[id] = st
+	}
+
+	x := s.Device.GamepadAxis(id, ebiten.StandardGamepadAxisLeftStickHorizontal)
+	y := s.Device.GamepadAxis(id, ebiten.StandardGamepadAxisLeftStickVertical)
+
+	d, held := stickDirection(x, y)
+
+	if !held {
+		st.held = false
+		return events
+	}
+
+	if !st.held || d != st.direction {
+		*st = stickState{direction: d, held: true}
+		return append(events, Event{Kind: EVENT_NAVIGATE, Direction: d})
+	}
+
+	st.ticks++
+
+	if st.ticks >= stickRepeatDelay && (st.ticks-stickRepeatDelay)%stickRepeatRate == 0 {
+		events = append(events, Event{Kind: EVENT_NAVIGATE, Direction: d})
+	}
+
+	return events
+}
+
+func stickDirection(x, y float64) (Direction, bool) {
+	ax, ay := x, y
+	if ax < 0 {
+		ax = -ax
+	}
+	if ay < 0 {
+		ay = -ay
+	}
+
+	if ax < stickDeadZone && ay < stickDeadZone {
+		return NAV_UP, false
+	}
+
+	if ax > ay {
+		if x < 0 {
+			return NAV_LEFT, true
+		}
+		return NAV_RIGHT, true
+	}
+
+	if y < 0 {
+		return NAV_UP, true
+	}
+	return NAV_DOWN, true
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Stats are the results of the finished rounds of one mode. Rounds played
+// with hints are counted in Hinted; a win with hints is a win, but it does
+// not extend the streak.
+type Stats struct {
+	Played    int    `json:"played"`
+	Won       int    `json:"won"`
+	Streak    int    `json:"streak"`
+	MaxStreak int    `json:"max_streak"`
+	Guesses   [6]int `json:"guesses"`
+	Hinted    int    `json:"hinted"`
+	Hints     int    `json:"hints"`
+}
+
+// Summary is what the score screen shows of the statistics.
+func (s Stats) Summary() string {
+	txt := fmt.Sprintf("игр: %d, побед: %d, серия: %d", s.Played, s.Won, s.Streak)
+	if s.Hinted > 0 {
+		txt += fmt.Sprintf("\nс подсказками: %d", s.Hinted)
+	}
+	return txt
+}
+
+var modeNames = map[Mode]string{
+	DAILY:    "daily",
+	ABSURDLE: "absurdle",
+}
+
+// StatsPath is where statistics are kept, next to the settings, or "" when
+// the platform has no config directory.
+func StatsPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "five-letters", "stats.json")
+}
+
+// LoadStats reads the statistics of every mode at path. A missing file
+// gives none.
+func LoadStats(path string) (map[string]Stats, error) {
+	all := map[string]Stats{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return all, nil
+	}
+	if err != nil {
+		return all, err
+	}
+
+	if err := json.Unmarshal(data, &all); err != nil {
+		return map[string]Stats{}, err
+	}
+
+	return all, nil
+}
+
+func SaveStats(path string, all map[string]Stats) error {
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Record adds a finished round won in guesses tries, or lost, with hints
+// taken along the way.
+func (s *Stats) Record(won bool, guesses, hints int) {
+	s.Played++
+
+	if hints > 0 {
+		s.Hinted++
+		s.Hints += hints
+	}
+
+	if !won {
+		s.Streak = 0
+		return
+	}
+
+	s.Won++
+	s.Guesses[guesses-1]++
+
+	if hints == 0 {
+		s.Streak++
+		s.MaxStreak = max(s.MaxStreak, s.Streak)
+	}
+}
+
+// LoadStats reads the statistics of the game's mode from g.StatsPath.
+func (g *Game) LoadStats() {
+	all, err := LoadStats(g.StatsPath)
+	if err != nil {
+		log.Println(err.Error())
+	}
+	g.Stats = all[modeNames[g.Mode]]
+}
+
+// RecordStats adds the finished round to the statistics and saves them.
+// Played back rounds are not counted again.
+func (g *Game) RecordStats() {
+	if g.Playback != nil {
+		return
+	}
+
+	g.Stats.Record(g.IsWordGuessed(), len(g.GuessedWords), len(g.Hints))
+
+	if g.StatsPath == "" {
+		return
+	}
+
+	all, err := LoadStats(g.StatsPath)
+	if err != nil {
+		log.Println(err.Error())
+	}
+	all[modeNames[g.Mode]] = g.Stats
+
+	if err := SaveStats(g.StatsPath, all); err != nil {
+		log.Println(err.Error())
+	}
+}
+
+var shareSquares = map[LetterStatus]string{
+	GUESSED: "🟩",
+	PRESENT: "🟨",
+	WRONG:   "⬛",
+}
+
+// ShareText sums up the finished round without giving the word away: the
+// result, the hints taken and a row of squares per guess.
+func (g *Game) ShareText() string {
+	result := "X"
+	if g.IsWordGuessed() {
+		result = fmt.Sprint(len(g.GuessedWords))
+	}
+
+	title := "Пять букв"
+	if g.Mode == ABSURDLE {
+		title += ", абсурдл"
+	}
+
+	lines := []string{fmt.Sprintf("%s %s/6", title, result)}
+
+	if len(g.Hints) > 0 {
+		lines = append(lines, fmt.Sprintf("подсказок: %d", len(g.Hints)))
+	}
+
+	lines = append(lines, "")
+
+	for _, p := range g.Feedback {
+		row := ""
+		for i := range wordLength {
+			row += shareSquares[p.At(i)]
+		}
+		lines = append(lines, row)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Share writes the share text to g.ShareOut.
+func (g *Game) Share() {
+	if g.ShareOut == nil {
+		return
+	}
+
+	if _, err := fmt.Fprintln(g.ShareOut, g.ShareText()); err != nil {
+		log.Println(err.Error())
+		return
+	}
+
+	g.Announce("Результат выведен")
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestStatsRecord(t *testing.T) {
+	var s Stats
+
+	s.Record(true, 3, 0)
+	s.Record(true, 4, 2)
+	if s.Won != 2 || s.Streak != 1 || s.Hinted != 1 || s.Hints != 2 {
+		t.Fatalf("after a hinted win %+v, want 2 wins, streak 1, one hinted round", s)
+	}
+	if s.Guesses[2] != 1 || s.Guesses[3] != 1 {
+		t.Fatalf("guess distribution %v", s.Guesses)
+	}
+
+	s.Record(false, 6, 0)
+	if s.Played != 3 || s.Streak != 0 || s.MaxStreak != 1 {
+		t.Fatalf("after a loss %+v, want 3 played and the streak reset", s)
+	}
+}
+
+func TestStatsPersist(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	h.g.StatsPath = filepath.Join(t.TempDir(), "stats.json")
+
+	h.guess("копна")
+	h.key(ebiten.KeyF1)
+	h.guess("вазон")
+
+	all, err := LoadStats(h.g.StatsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := all["daily"]
+	if s.Played != 1 || s.Won != 1 || s.Hinted != 1 || s.Streak != 0 || s.Guesses[1] != 1 {
+		t.Fatalf("saved %+v, want one hinted win in two guesses", s)
+	}
+
+	n := newHarness(t, DAILY, "вазон")
+	n.g.StatsPath = h.g.StatsPath
+	n.g.LoadStats()
+	if n.g.Stats != s {
+		t.Fatalf("loaded %+v, want %+v", n.g.Stats, s)
+	}
+}
+
+func TestShareText(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	var out bytes.Buffer
+	h.g.ShareOut = &out
+
+	h.guess("копна")
+	h.key(ebiten.KeyF1)
+	h.key(ebiten.KeyF7)
+	if out.Len() != 0 {
+		t.Fatalf("shared %q before the round ended", out.String())
+	}
+
+	h.guess("вазон")
+	h.key(ebiten.KeyF7)
+
+	want := "Пять букв 2/6\nподсказок: 1\n\n⬛🟨⬛🟨🟨\n🟩🟩🟩🟩🟩\n"
+	if out.String() != want {
+		t.Fatalf("shared %q, want %q", out.String(), want)
+	}
+}
@@ -2,1241 +2,199 @@ package main
 
 import (
 	"image/color"
+	"math"
+	"strings"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
-const LetterWidth = 8
+// LetterWidth and LetterHeight are the cell size of TextFont, which text is
+// laid out by.
+var (
+	LetterWidth  = float32(TextFont.CellWidth)
+	LetterHeight = float32(TextFont.CellHeight)
+)
+
+func SetTextFont(f *Font) {
+	TextFont = f
+	LetterWidth = float32(f.CellWidth)
+	LetterHeight = float32(f.CellHeight)
+}
 
+// DrawText draws txt with the current Renderer, the top left of its first
+// cell at x, y, moving right by each letter's advance.
 func DrawText(screen *ebiten.Image, txt string, x, y, s float32, c color.Color) {
-	for i, l := range txt {
-		DrawLetter(
-			screen,
-			l,
-			x+float32(i)*(LetterWidth*s),
-			y,
-			s,
-			c,
-		)
+	for _, l := range txt {
+		Renderer.DrawLetter(screen, l, x, y, s, c)
+		x += Renderer.Advance(l, s)
 	}
 }
 
 func DrawLetter(screen *ebiten.Image, l rune, x, y, s float32, c color.Color) {
-	m := GetLetterMap(l)
-	DrawBitmap(screen, m, x, y, s, LetterWidth, c)
+	Renderer.DrawLetter(screen, l, x, y, s, c)
 }
 
-func DrawBitmap(
-	screen *ebiten.Image,
-	m *[]byte,
-	x, y, s float32,
-	l int,
-	c color.Color,
-) {
-	i := 0
-
-	for j := range l {
-		for k := range l {
-			pX := x + float32(k)*s
-			pY := y + float32(j)*s
+type Align byte
 
-			if (*m)[i] == 1 {
-				vector.FillRect(screen, pX, pY, s, s, c, false)
-			}
+const (
+	ALIGN_START Align = iota
+	ALIGN_CENTER
+	ALIGN_END
+)
 
-			i++
-		}
-	}
+// LineGap is the space between lines, in units of the letter scale.
+const LineGap = 4
+
+// TextBox is the rectangle DrawTextAligned lays text out in. Align places
+// each line horizontally and VAlign the block of lines vertically. With
+// Wrap set, lines longer than W break between words.
+type TextBox struct {
+	X, Y, W, H float32
+	Align      Align
+	VAlign     Align
+	Wrap       bool
 }
 
-func GetLetterMap(l rune) *[]byte {
-	switch l {
-	case 'a':
-		return &[]byte{
-			0, 0, 0, 1, 1, 0, 0, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-	case 'b':
-		return &[]byte{
-			0, 1, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-	case 'c':
-		return &[]byte{
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-	case 'd':
-		return &[]byte{
-			0, 1, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-	case 'e':
-		return &[]byte{
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case 'f':
-		return &[]byte{
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case 'g':
-		return &[]byte{
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 1, 1, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case 'h':
-		return &[]byte{
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case 'i':
-		return &[]byte{
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case 'j':
-		return &[]byte{
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 1, 1, 1, 1, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case 'k':
-		return &[]byte{
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 1, 0, 0, 0,
-			0, 1, 0, 1, 0, 0, 0, 0,
-			0, 1, 1, 1, 0, 0, 0, 0,
-			0, 1, 0, 0, 1, 0, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case 'l':
-		return &[]byte{
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case 'm':
-		return &[]byte{
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 1, 0, 0, 1, 1, 0,
-			0, 1, 0, 1, 1, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case 'n':
-		return &[]byte{
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 1, 0, 0, 0, 1, 0,
-			0, 1, 0, 1, 0, 0, 1, 0,
-			0, 1, 0, 0, 1, 0, 1, 0,
-			0, 1, 0, 0, 0, 1, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case 'o':
-		return &[]byte{
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case 'p':
-		return &[]byte{
-			0, 1, 1, 1, 1, 0, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 1, 1, 1, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case 'q':
-		return &[]byte{
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 1, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case 'r':
-		return &[]byte{
-			0, 1, 1, 1, 1, 0, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 1, 1, 1, 0, 0, 0,
-			0, 1, 0, 0, 1, 0, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case 's':
-		return &[]byte{
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case 't':
-		return &[]byte{
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case 'u':
-		return &[]byte{
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case 'v':
-		return &[]byte{
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 0, 0, 1, 1, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case 'w':
-		return &[]byte{
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 1, 1, 0, 1, 0,
-			0, 1, 1, 0, 0, 1, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case 'x':
-		return &[]byte{
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 0, 0, 1, 1, 0, 0, 0,
-			0, 0, 0, 1, 1, 0, 0, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case 'y':
-		return &[]byte{
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case 'z':
-		return &[]byte{
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 1, 0, 0, 0, 0, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '$':
-		return &[]byte{
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 1, 0, 0, 1, 0,
-			0, 1, 0, 1, 0, 0, 0, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 1, 0, 0, 1, 0,
-			0, 1, 0, 1, 0, 0, 1, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '&':
-		return &[]byte{
-			0, 0, 1, 1, 1, 0, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 0, 1, 0, 0, 0, 0, 0,
-			0, 1, 0, 1, 1, 0, 0, 0,
-			0, 1, 0, 1, 1, 0, 0, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '@':
-		return &[]byte{
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 1, 0, 1, 0,
-			0, 1, 0, 1, 0, 1, 1, 0,
-			0, 1, 0, 1, 0, 0, 1, 0,
-			0, 1, 0, 0, 1, 0, 1, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '#':
-		return &[]byte{
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '%':
-		return &[]byte{
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 1, 1, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '*':
-		return &[]byte{
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 1, 0, 1, 0, 0,
-			0, 0, 1, 1, 1, 0, 0, 0,
-			0, 1, 1, 1, 1, 1, 0, 0,
-			0, 0, 1, 1, 1, 0, 0, 0,
-			0, 1, 0, 1, 0, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '?':
-		return &[]byte{
-			0, 0, 0, 1, 1, 1, 0, 0,
-			0, 0, 1, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 1, 1, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '/':
-		return &[]byte{
-			0, 0, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 1, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '\\':
-		return &[]byte{
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 0, 1, 0, 0, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '~':
-		return &[]byte{
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 1, 0, 1, 1, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '`':
-		return &[]byte{
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 1, 0, 0, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '^':
-		return &[]byte{
-			0, 0, 0, 1, 1, 0, 0, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '(':
-		return &[]byte{
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 1, 1, 0, 0, 0, 0,
-			0, 0, 1, 0, 0, 0, 0, 0,
-			0, 0, 1, 1, 0, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case ')':
-		return &[]byte{
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '>':
-		return &[]byte{
-			0, 0, 1, 0, 0, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 1, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '<':
-		return &[]byte{
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 1, 0, 0, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '[':
-		return &[]byte{
-			0, 0, 0, 1, 1, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 0, 1, 1, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case ']':
-		return &[]byte{
-			0, 0, 0, 1, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 1, 1, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '{':
-		return &[]byte{
-			0, 0, 0, 0, 1, 1, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 1, 1, 1, 0, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 0, 0, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '}':
-		return &[]byte{
-			0, 0, 1, 1, 0, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 1, 1, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 1, 1, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '|':
-		return &[]byte{
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '!':
-		return &[]byte{
-			0, 1, 1, 0, 0, 0, 0, 0,
-			0, 1, 1, 0, 0, 0, 0, 0,
-			0, 1, 1, 0, 0, 0, 0, 0,
-			0, 1, 1, 0, 0, 0, 0, 0,
-			0, 1, 1, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 1, 1, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '.':
-		return &[]byte{
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 1, 1, 0, 0, 0, 0, 0,
-			0, 1, 1, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case ',':
-		return &[]byte{
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 1, 1, 0, 0, 0, 0, 0,
-			0, 1, 1, 0, 0, 0, 0, 0,
-			0, 0, 1, 0, 0, 0, 0, 0,
-			0, 1, 1, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case ':':
-		return &[]byte{
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 1, 1, 0, 0, 0,
-			0, 0, 0, 1, 1, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 1, 1, 0, 0, 0,
-			0, 0, 0, 1, 1, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case ';':
-		return &[]byte{
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 1, 1, 0, 0, 0,
-			0, 0, 0, 1, 1, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 1, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 1, 1, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '"':
-		return &[]byte{
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '\'':
-		return &[]byte{
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '-':
-		return &[]byte{
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-
-	case '_':
-		return &[]byte{
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
+// CenteredIn returns a box centring text on the given rectangle.
+func CenteredIn(x, y, w, h float32) TextBox {
+	return TextBox{X: x, Y: y, W: w, H: h, Align: ALIGN_CENTER, VAlign: ALIGN_CENTER}
+}
 
-	case '+':
-		return &[]byte{
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
+// TextWidth is the width of a single line of txt.
+func TextWidth(txt string, s float32) float32 {
+	w := float32(0)
+	for _, l := range txt {
+		w += Renderer.Advance(l, s)
+	}
+	return w
+}
 
-	case '=':
-		return &[]byte{
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
+// MeasureText returns the size of txt, which may span several lines.
+func MeasureText(txt string, s float32) (float32, float32) {
+	return measureLines(strings.Split(txt, "\n"), s)
+}
 
-	case '0':
-		return &[]byte{
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 1, 1, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
+func measureLines(lines []string, s float32) (float32, float32) {
+	w := float32(0)
+	for _, line := range lines {
+		w = max(w, TextWidth(line, s))
+	}
 
-	case '1':
-		return &[]byte{
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 1, 1, 0, 0, 0,
-			0, 0, 1, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
+	n := float32(len(lines))
+	return w, n*LetterHeight*s + (n-1)*LineGap*s
+}
 
-	case '2':
-		return &[]byte{
-			0, 0, 1, 1, 1, 0, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 1, 0, 0, 0, 0, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
+// WrapText splits txt into lines no wider than w, breaking between words
+// where it can and inside a word only when the word alone is too wide.
+// Without any room to wrap into, it keeps each line whole.
+func WrapText(txt string, w, s float32) []string {
+	if w <= 0 {
+		return strings.Split(txt, "\n")
+	}
 
-	case '3':
-		return &[]byte{
-			0, 0, 1, 1, 1, 0, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 0, 1, 1, 1, 0, 0, 0,
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 1, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
+	lines := make([]string, 0, 4)
 
-	case '4':
-		return &[]byte{
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
+	for _, para := range strings.Split(txt, "\n") {
+		line := ""
 
-	case '5':
-		return &[]byte{
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 1, 0,
-			0, 1, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
+		for _, word := range strings.Fields(para) {
+			next := word
+			if line != "" {
+				next = line + " " + word
+			}
 
-	case '6':
-		return &[]byte{
-			0, 0, 0, 0, 1, 1, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 1, 0, 0, 0, 0, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
+			if TextWidth(next, s) <= w {
+				line = next
+				continue
+			}
 
-	case '7':
-		return &[]byte{
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 1, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
+			if line != "" {
+				lines = append(lines, line)
+			}
 
-	case '8':
-		return &[]byte{
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
+			for TextWidth(word, s) > w {
+				cut := fitRunes(word, w, s)
+				lines = append(lines, word[:cut])
+				word = word[cut:]
+			}
+			line = word
 		}
 
-	case '9':
-		return &[]byte{
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 1, 1, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-	case 'а':
-		return &[]byte{
-			0, 0, 0, 1, 1, 0, 0, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-	case 'б':
-		return &[]byte{
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-	case 'в':
-		return &[]byte{
-			0, 1, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-	case 'г':
-		return &[]byte{
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-	case 'д':
-		return &[]byte{
-			0, 0, 0, 1, 1, 1, 0, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-	case 'е':
-		return &[]byte{
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-	case 'ж':
-		return &[]byte{
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 1, 1, 0, 1, 0,
-			0, 1, 0, 1, 1, 0, 1, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 1, 1, 0, 1, 0,
-			0, 1, 0, 1, 1, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-	case 'з':
-		return &[]byte{
-			0, 1, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 1, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 1, 0,
-			0, 1, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-	case 'и':
-		return &[]byte{
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 1, 1, 0,
-			0, 1, 0, 0, 1, 0, 1, 0,
-			0, 1, 0, 1, 0, 0, 1, 0,
-			0, 1, 1, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-	case 'й':
-		return &[]byte{
-			0, 0, 0, 1, 1, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 1, 1, 0,
-			0, 1, 0, 0, 1, 0, 1, 0,
-			0, 1, 0, 1, 0, 0, 1, 0,
-			0, 1, 1, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-	case 'к':
-		return &[]byte{
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 1, 0, 0, 0,
-			0, 1, 1, 1, 0, 0, 0, 0,
-			0, 1, 0, 0, 1, 0, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-	case 'л':
-		return &[]byte{
-			0, 0, 0, 1, 1, 1, 0, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-	case 'п':
-		return &[]byte{
-			0, 1, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-	case 'м':
-		return &[]byte{
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 1, 0, 0, 1, 1, 0,
-			0, 1, 0, 1, 1, 0, 1, 0,
-			0, 1, 0, 1, 1, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-	case 'н':
-		return &[]byte{
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-	case 'о':
-		return &[]byte{
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
+		lines = append(lines, line)
+	}
 
-	case 'х':
-		return &[]byte{
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 0, 0, 1, 1, 0, 0, 0,
-			0, 0, 0, 1, 1, 0, 0, 0,
-			0, 0, 1, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
+	return lines
+}
 
-	case 'у':
-		return &[]byte{
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 1, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
+// fitRunes is the byte length of the longest prefix of word that fits in w,
+// at least one rune.
+func fitRunes(word string, w, s float32) int {
+	width := float32(0)
+	for i, l := range word {
+		width += Renderer.Advance(l, s)
+		if width > w && i > 0 {
+			return i
 		}
+	}
+	return len(word)
+}
 
-	case 'с':
-		return &[]byte{
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
+// FitScale is the largest whole scale up to s at which txt is no wider
+// than w.
+func FitScale(txt string, w, s float32) float32 {
+	width, _ := MeasureText(txt, 1)
+	if width <= 0 {
+		return s
+	}
+	return max(1, min(s, float32(math.Floor(float64(w/width)))))
+}
 
-	case 'т':
-		return &[]byte{
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
+// DrawTextAligned draws txt inside box according to its alignment.
+func DrawTextAligned(screen *ebiten.Image, txt string, box TextBox, s float32, c color.Color) {
+	lines := strings.Split(txt, "\n")
+	if box.Wrap {
+		lines = WrapText(txt, box.W, s)
+	}
 
-	case 'э':
-		return &[]byte{
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 1, 0,
-			0, 0, 0, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-	case 'ь':
-		return &[]byte{
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
+	_, h := measureLines(lines, s)
+	y := box.Y + aligned(box.VAlign, box.H, h)
 
-	case 'ъ':
-		return &[]byte{
-			1, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
+	for _, line := range lines {
+		x := box.X + aligned(box.Align, box.W, TextWidth(line, s))
+		DrawText(screen, line, x, y, s, c)
+		y += (LetterHeight + LineGap) * s
+	}
+}
 
-	case 'ы':
-		return &[]byte{
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 1, 1, 0, 0, 1, 0,
-			0, 1, 0, 0, 1, 0, 1, 0,
-			0, 1, 0, 0, 1, 0, 1, 0,
-			0, 1, 1, 1, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
+func aligned(a Align, space, size float32) float32 {
+	switch a {
+	case ALIGN_CENTER:
+		return (space - size) / 2
+	case ALIGN_END:
+		return space - size
+	}
+	return 0
+}
 
-	case 'ю':
-		return &[]byte{
-			0, 1, 0, 0, 1, 1, 0, 0,
-			0, 1, 0, 1, 0, 0, 1, 0,
-			0, 1, 0, 1, 0, 0, 1, 0,
-			0, 1, 1, 1, 0, 0, 1, 0,
-			0, 1, 0, 1, 0, 0, 1, 0,
-			0, 1, 0, 1, 0, 0, 1, 0,
-			0, 1, 0, 0, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
+// drawBits draws a bitmap w bits wide. The corner is snapped to the pixel
+// grid and s to a whole number so every bit covers exactly s×s pixels.
+func drawBits(screen *ebiten.Image, m []byte, w int, x, y, s float32, c color.Color) {
+	x = float32(math.Round(float64(x)))
+	y = float32(math.Round(float64(y)))
+	s = max(1, float32(math.Floor(float64(s))))
 
-	case 'ш':
-		return &[]byte{
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 1, 0, 1, 0,
-			0, 1, 0, 0, 1, 0, 1, 0,
-			0, 1, 0, 0, 1, 0, 1, 0,
-			0, 1, 0, 0, 1, 0, 1, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-	case 'щ':
-		return &[]byte{
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 1, 0,
-			0, 1, 0, 0, 1, 0, 1, 0,
-			0, 1, 0, 0, 1, 0, 1, 0,
-			0, 1, 0, 0, 1, 0, 1, 0,
-			0, 1, 0, 0, 1, 0, 1, 0,
-			0, 1, 1, 1, 1, 1, 1, 1,
-			0, 0, 0, 0, 0, 0, 0, 1,
-		}
+	i := 0
 
-	case 'р':
-		return &[]byte{
-			0, 1, 1, 1, 1, 0, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 1, 1, 1, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
-	case 'ц':
-		return &[]byte{
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 1, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 0, 0, 1, 0,
-		}
-	case 'ч':
-		return &[]byte{
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 1, 0, 0, 0, 1, 0, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 0, 1, 0, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
-		}
+	for j := range len(m) / w {
+		for k := range w {
+			pX := x + float32(k)*s
+			pY := y + float32(j)*s
 
-	case 'ф':
-		return &[]byte{
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 1, 0, 0, 1, 0, 1, 0,
-			0, 1, 0, 0, 1, 0, 1, 0,
-			0, 1, 0, 0, 1, 0, 1, 0,
-			0, 0, 1, 1, 1, 1, 0, 0,
-			0, 0, 0, 0, 1, 0, 0, 0,
-		}
+			if m[i] == 1 {
+				fillRect(screen, pX, pY, s, s, c)
+			}
 
-	case 'я':
-		return &[]byte{
-			0, 0, 0, 0, 0, 0, 0, 0,
-			0, 0, 0, 1, 1, 1, 1, 0,
-			0, 0, 1, 0, 0, 0, 1, 0,
-			0, 0, 1, 0, 0, 0, 1, 0,
-			0, 0, 0, 1, 1, 1, 1, 0,
-			0, 0, 0, 0, 1, 0, 1, 0,
-			0, 0, 0, 1, 0, 0, 1, 0,
-			0, 0, 0, 0, 0, 0, 0, 0,
+			i++
 		}
 	}
-
-	return &[]byte{
-		0, 0, 0, 0, 0, 0, 0, 0,
-		0, 0, 0, 0, 0, 0, 0, 0,
-		0, 0, 0, 0, 0, 0, 0, 0,
-		0, 0, 0, 0, 0, 0, 0, 0,
-		0, 0, 0, 0, 0, 0, 0, 0,
-		0, 0, 0, 0, 0, 0, 0, 0,
-		0, 0, 0, 0, 0, 0, 0, 0,
-		0, 0, 0, 0, 0, 0, 0, 0,
-	}
 }
@@ -0,0 +1,151 @@
+//go:build draw
+
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/e-kucheriavyi/five-letters/pallete"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestAtlasMatchesBitmap(t *testing.T) {
+	for _, s := range []float32{1, 3, 4} {
+		want := ebiten.NewImage(64, 64)
+		got := ebiten.NewImage(64, 64)
+
+		for _, l := range "вазон" {
+			want.Clear()
+			got.Clear()
+
+			g := TextFont.Glyph(l)
+			drawBits(want, g.Bits, g.Width, 3, 5+float32(g.Y)*s, s, pallete.Dark.Foreground)
+			DrawLetter(got, l, 3, 5, s, pallete.Dark.Foreground)
+
+			if !bytes.Equal(pixels(want), pixels(got)) {
+				t.Fatalf("%c at scale %v differs from its bitmap", l, s)
+			}
+		}
+	}
+}
+
+func TestBackspaceIconFromAtlas(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	screen := ebiten.NewImage(screenW, screenH)
+	key := FindNode(h.g.Node, "key_-")
+	h.g.DrawKey(screen, key)
+
+	drawCalls = 0
+	h.g.DrawKey(screen, key)
+	if drawCalls != 2 {
+		t.Fatalf("backspace key took %d draw calls, want the fill and one glyph", drawCalls)
+	}
+
+	want := ebiten.NewImage(16, 16)
+	got := ebiten.NewImage(16, 16)
+	icon := iconFont.Glyph('-')
+	drawBits(want, icon.Bits, icon.Width, 2, 3, 1, pallete.Dark.Foreground)
+	drawGlyph(got, iconFont, '-', 2, 3, 1, pallete.Dark.Foreground)
+	if !bytes.Equal(pixels(want), pixels(got)) {
+		t.Fatal("backspace icon from the atlas differs from its bitmap")
+	}
+}
+
+func pixels(img *ebiten.Image) []byte {
+	p := image.NewRGBA(img.Bounds())
+	img.ReadPixels(p.Pix)
+	return p.Pix
+}
+
+// pixelRenderer draws TextFont a rect per lit pixel, the way letters were
+// drawn before the glyph atlas.
+type pixelRenderer struct{}
+
+func (pixelRenderer) DrawLetter(screen *ebiten.Image, l rune, x, y, s float32, c color.Color) {
+	g := TextFont.Glyph(l)
+	drawBits(screen, g.Bits, g.Width, x+float32(g.X)*s, y+float32(g.Y)*s, s, c)
+}
+
+func (pixelRenderer) Advance(l rune, s float32) float32 {
+	return BitmapRenderer{}.Advance(l, s)
+}
+
+// BenchmarkDrawFrame draws a round in progress with letters drawn a rect
+// per lit pixel and from the glyph atlas. draws/op counts the calls into
+// ebiten's drawing API during one frame.
+func BenchmarkDrawFrame(b *testing.B) {
+	cases := []struct {
+		name     string
+		renderer TextRenderer
+	}{
+		{"bitmap", pixelRenderer{}},
+		{"atlas", BitmapRenderer{}},
+	}
+
+	for _, c := range cases {
+		b.Run(c.name, func(b *testing.B) {
+			h := newHarness(b, DAILY, "вазон")
+			h.guess("копна")
+			h.guess("сазан")
+			h.typeRunes("ва")
+
+			Renderer = c.renderer
+			defer SetTextRenderer(TEXT_PIXEL)
+
+			screen := ebiten.NewImage(screenW, screenH)
+			h.g.Draw(screen)
+
+			drawCalls = 0
+			frames := 0
+
+			b.ReportAllocs()
+			for b.Loop() {
+				h.g.Draw(screen)
+				frames++
+			}
+			b.ReportMetric(float64(drawCalls)/float64(frames), "draws/op")
+		})
+	}
+}
+
+func TestTrueTypeRenderer(t *testing.T) {
+	h := newHarness(t, DAILY, "вазон")
+	h.guess("копна")
+
+	bitmap := ebiten.NewImage(screenW, screenH)
+	h.g.Draw(bitmap)
+
+	h.g.ChangeSetting(SETTING_TEXT, 1)
+	defer SetTextRenderer(TEXT_PIXEL)
+
+	if got, want := Renderer.Advance('ж', 4), (BitmapRenderer{}).Advance('ж', 4); got != want {
+		t.Fatalf("advance %v, want the pixel font's %v", got, want)
+	}
+
+	smooth := ebiten.NewImage(screenW, screenH)
+	h.g.Draw(smooth)
+
+	got := image.NewRGBA(smooth.Bounds())
+	smooth.ReadPixels(got.Pix)
+
+	key := FindNode(h.g.Node, "key_ж")
+	fill := got.RGBAAt(int(key.X)+2, int(key.Y)+2)
+	inked := 0
+	for y := int(key.Y); y < int(key.Y+key.H); y++ {
+		for x := int(key.X); x < int(key.X+key.W); x++ {
+			if got.RGBAAt(x, y) != fill {
+				inked++
+			}
+		}
+	}
+	if inked == 0 {
+		t.Fatal("no glyph drawn on key_ж")
+	}
+
+	if bytes.Equal(pixels(bitmap), got.Pix) {
+		t.Fatal("the TrueType frame is identical to the bitmap one")
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMeasureText(t *testing.T) {
+	if w, h := MeasureText("вазон", 2); w != 80 || h != 16 {
+		t.Fatalf("MeasureText(вазон) = %v, %v; want 80, 16", w, h)
+	}
+
+	if w, h := MeasureText("да\nвазон", 1); w != 40 || h != 8+LineGap+8 {
+		t.Fatalf("two lines = %v, %v; want 40, %v", w, h, 8+LineGap+8)
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	cases := []struct {
+		txt  string
+		w    float32
+		want []string
+	}{
+		{"подсказок: 3", 200, []string{"подсказок: 3"}},
+		{"подсказок: 3", 80, []string{"подсказок:", "3"}},
+		{"подсказок", 40, []string{"подск", "азок"}},
+		{"да\nнет", 200, []string{"да", "нет"}},
+		{"подсказок: 3", 0, []string{"подсказок: 3"}},
+		{"да\nнет", -10, []string{"да", "нет"}},
+	}
+
+	for _, c := range cases {
+		got := WrapText(c.txt, c.w, 1)
+		if strings.Join(got, "|") != strings.Join(c.want, "|") {
+			t.Errorf("WrapText(%q, %v) = %q, want %q", c.txt, c.w, got, c.want)
+		}
+	}
+}
+
+func TestFitScale(t *testing.T) {
+	if s := FitScale("вазон", 1000, 3); s != 3 {
+		t.Fatalf("roomy FitScale = %v, want 3", s)
+	}
+	if s := FitScale("вазон", 90, 3); s != 2 {
+		t.Fatalf("tight FitScale = %v, want 2", s)
+	}
+	if s := FitScale("вазон", 10, 3); s != 1 {
+		t.Fatalf("FitScale never goes below 1, got %v", s)
+	}
+}
+
+func TestAligned(t *testing.T) {
+	if got := aligned(ALIGN_START, 100, 40); got != 0 {
+		t.Errorf("start = %v", got)
+	}
+	if got := aligned(ALIGN_CENTER, 100, 40); got != 30 {
+		t.Errorf("center = %v", got)
+	}
+	if got := aligned(ALIGN_END, 100, 40); got != 60 {
+		t.Errorf("end = %v", got)
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"strings"
+)
+
+type InputLayout byte
+
+const (
+	// LAYOUT_CHARS takes the characters the OS produced, so any Cyrillic
+	// layout works as long as it is the active one.
+	LAYOUT_CHARS InputLayout = iota
+	// LAYOUT_PHYSICAL maps US key positions onto ЙЦУКЕН regardless of the
+	// active layout.
+	LAYOUT_PHYSICAL
+	// LAYOUT_TRANSLIT spells Cyrillic with Latin letters, "sh" for "ш".
+	LAYOUT_TRANSLIT
+)
+
+var inputLayoutNames = []string{"chars", "physical", "translit"}
+
+func (l InputLayout) String() string {
+	return inputLayoutNames[l]
+}
+
+func ParseInputLayout(name string) (InputLayout, bool) {
+	for i, n := range inputLayoutNames {
+		if n == name {
+			return InputLayout(i), true
+		}
+	}
+	return LAYOUT_CHARS, false
+}
+
+var translitSingle = map[rune]rune{
+	'a':  'а',
+	'b':  'б',
+	'v':  'в',
+	'w':  'в',
+	'g':  'г',
+	'd':  'д',
+	'e':  'е',
+	'z':  'з',
+	'i':  'и',
+	'j':  'й',
+	'k':  'к',
+	'l':  'л',
+	'm':  'м',
+	'n':  'н',
+	'o':  'о',
+	'p':  'п',
+	'r':  'р',
+	's':  'с',
+	't':  'т',
+	'u':  'у',
+	'f':  'ф',
+	'h':  'х',
+	'c':  'ц',
+	'y':  'ы',
+	'q':  'я',
+	'\'': 'ь',
+	'"':  'ъ',
+}
+
+var translitMulti = map[string]rune{
+	"zh":   'ж',
+	"kh":   'х',
+	"ts":   'ц',
+	"ch":   'ч',
+	"sh":   'ш',
+	"shch": 'щ',
+	"yu":   'ю',
+	"ya":   'я',
+	"yo":   'е',
+	"ye":   'э',
+}
+
+func IsAlphabetLetter(l rune) bool {
+	return strings.ContainsRune(alphabet, l)
+}
+
+// NormalizeLetter folds input onto the letters used by the dictionary.
+func NormalizeLetter(l rune) rune {
+	if l == 'ё' || l == 'Ё' {
+		return 'е'
+	}
+	if l >= 'А' && l <= 'Я' {
+		return l + ('а' - 'А')
+	}
+	return l
+}
+
+// Transliterator converts Latin input as it is typed. A letter is shown at
+// once and replaced when the next key completes a longer sequence, so "s"
+// shows "с" and a following "h" turns it into "ш".
+type Transliterator struct {
+	pending string
+}
+
+func (t *Transliterator) Reset() {
+	t.pending = ""
+}
+
+// Feed appends the event produced by typing c: the new letters and how
+// many of the letters typed before them they replace.
+func (t *Transliterator) Feed(c rune, events []Event) []Event {
+	c = []rune(strings.ToLower(string(c)))[0]
+
+	if _, ok := translitSingle[c]; !ok && !isTranslitPrefix(string(c)) {
+		t.pending = ""
+		return events
+	}
+
+	next := t.pending + string(c)
+	replace := 0
+
+	if t.pending != "" && isTranslitPrefix(next) {
+		replace = len(transliterate(t.pending))
+	} else {
+		next = string(c)
+	}
+
+	t.pending = next
+	if !isTranslitPrefix(next) {
+		t.pending = ""
+	}
+
+	return append(events, Event{Kind: EVENT_COMPOSE, Text: string(transliterate(next)), Replace: replace})
+}
+
+func isTranslitPrefix(s string) bool {
+	for k := range translitMulti {
+		if strings.HasPrefix(k, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// transliterate converts s greedily, longest sequence first.
+func transliterate(s string) []rune {
+	out := make([]rune, 0, len(s))
+
+	for len(s) > 0 {
+		matched := false
+
+		for n := min(len(s), 4); n > 1; n-- {
+			if l, ok := translitMulti[s[:n]]; ok {
+				out = append(out, l)
+				s = s[n:]
+				matched = true
+				break
+			}
+		}
+
+		if matched {
+			continue
+		}
+
+		if l, ok := translitSingle[rune(s[0])]; ok {
+			out = append(out, l)
+		}
+		s = s[1:]
+	}
+
+	return out
+}
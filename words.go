@@ -10,20 +10,30 @@ import (
 //go:embed russian.txt
 var f []byte
 
+var dictionary []string
+
+func Dictionary() []string {
+	if dictionary == nil {
+		dictionary = strings.Split(strings.ToLower(string(f)), "\n")
+	}
+
+	return dictionary
+}
+
 func GetWord(t time.Time) string {
 	year, month, day := t.Date()
 	s := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
 
 	r := rand.New(rand.NewSource(s.Unix()))
 
-	var words []string = strings.Split(strings.ToLower(string(f)), "\n")
+	words := Dictionary()
 	i := r.Intn(len(words) - 1)
 
 	return words[i]
 }
 
 func ValidateWord(guess string) bool {
-	var words []string = strings.Split(strings.ToLower(string(f)), "\n")
+	words := Dictionary()
 
 	for i := range len(words) {
 		word := words[i]